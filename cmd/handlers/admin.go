@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"crypto/subtle"
+
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/valyala/fasthttp"
+)
+
+// WithAdminToken lets the request through to next only when it carries
+// "Authorization: Bearer <token>"; any other request is answered 401.
+func WithAdminToken(token string, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	expected := []byte("Bearer " + token)
+	return func(c *fasthttp.RequestCtx) {
+		if token == "" || subtle.ConstantTimeCompare(c.Request.Header.Peek("Authorization"), expected) != 1 {
+			body, _ := models.RespError{Message: "admin token required"}.MarshalJSON()
+			c.Response.Header.Set("WWW-Authenticate", "Bearer")
+			c.SetContentType("application/json")
+			c.SetStatusCode(fasthttp.StatusUnauthorized)
+			c.Write(body)
+			return
+		}
+		next(c)
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestWithAdminToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		status        int
+	}{
+		{"matching token", "secret", "Bearer secret", fasthttp.StatusOK},
+		{"no header", "secret", "", fasthttp.StatusUnauthorized},
+		{"wrong token", "secret", "Bearer guess", fasthttp.StatusUnauthorized},
+		{"bare token", "secret", "secret", fasthttp.StatusUnauthorized},
+		{"no token configured", "", "Bearer ", fasthttp.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := WithAdminToken(tt.token, func(c *fasthttp.RequestCtx) {
+				called = true
+				c.SetStatusCode(fasthttp.StatusOK)
+			})
+
+			c := &fasthttp.RequestCtx{}
+			if tt.authorization != "" {
+				c.Request.Header.Set("Authorization", tt.authorization)
+			}
+			handler(c)
+
+			if got := c.Response.StatusCode(); got != tt.status {
+				t.Errorf("status = %d, want %d", got, tt.status)
+			}
+			if called != (tt.status == fasthttp.StatusOK) {
+				t.Errorf("next called = %v", called)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/EgorAist/TP_DB_project/internal/logger"
+	"github.com/valyala/fasthttp"
+)
+
+const requestContextKey = "requestContext"
+
+// WithTimeout runs next with a request context that expires after timeout
+// (no deadline when timeout is zero) and carries the request logger set by
+// WithLogging. The context deliberately does not inherit cancellation from
+// the RequestCtx: fasthttp closes its Done channel as soon as Shutdown
+// starts, which would cancel the requests we drain.
+func WithTimeout(timeout time.Duration, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		var ctx context.Context
+		var cancel context.CancelFunc
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		} else {
+			ctx, cancel = context.WithCancel(context.Background())
+		}
+		defer cancel()
+
+		if log, ok := requestLogger(c); ok {
+			ctx = logger.NewContext(ctx, log)
+		}
+
+		c.SetUserValue(requestContextKey, ctx)
+		next(c)
+	}
+}
+
+func requestContext(c *fasthttp.RequestCtx) context.Context {
+	if ctx, ok := c.UserValue(requestContextKey).(context.Context); ok {
+		return ctx
+	}
+	return context.Background()
+}
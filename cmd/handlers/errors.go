@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/valyala/fasthttp"
+)
+
+// statusCode maps an error returned by the service to its HTTP status.
+// Errors that are not models.Error are unexpected and reported as 500.
+func statusCode(err error) int {
+	var e models.Error
+	if !errors.As(err, &e) {
+		return fasthttp.StatusInternalServerError
+	}
+
+	switch e.Kind {
+	case models.KindNotFound:
+		return fasthttp.StatusNotFound
+	case models.KindConflict:
+		return fasthttp.StatusConflict
+	case models.KindValidation:
+		return fasthttp.StatusBadRequest
+	case models.KindUnavailable:
+		return fasthttp.StatusServiceUnavailable
+	case models.KindTimeout:
+		return fasthttp.StatusGatewayTimeout
+	default:
+		return fasthttp.StatusInternalServerError
+	}
+}
+
+// WriteError writes err as a models.RespError. The cause of internal errors
+// is logged but never sent to the client.
+func (h handler) WriteError(c *fasthttp.RequestCtx, err error) {
+	status := statusCode(err)
+
+	resp := models.RespError{Message: "internal error"}
+	var e models.Error
+	if errors.As(err, &e) && e.Kind != models.KindInternal {
+		resp = models.RespError{Message: e.Message, Entity: e.Entity, Reason: e.Reason, Fields: e.Fields}
+	}
+	if status >= fasthttp.StatusInternalServerError {
+		h.log.For(requestContext(c)).Error("request failed", "status", status, "error", err)
+	}
+
+	body, _ := resp.MarshalJSON()
+	h.WriteResponse(c, status, body)
+}
@@ -2,9 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/EgorAist/TP_DB_project/internal/models"
 	"github.com/valyala/fasthttp"
-	"log"
+	"strings"
 )
 
 
@@ -12,19 +13,22 @@ func (h handler) ForumCreate(c *fasthttp.RequestCtx) {
 	forumInput := &models.ForumCreate{}
 	err := forumInput.UnmarshalJSON(c.PostBody())
 	if err != nil {
-		log.Println(err)
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+	if err = forumInput.Validate(); err != nil {
+		h.WriteError(c, err)
 		return
 	}
 
-	forum, err := h.Service.CreateForum(*forumInput)
+	forum, err := h.Service.CreateForum(requestContext(c), *forumInput)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		if status == fasthttp.StatusConflict {
+		if errors.Is(err, models.ErrConflict) {
 			response, _ := forum.MarshalJSON()
-			h.WriteResponse(c, status, response)
+			h.WriteResponse(c, fasthttp.StatusConflict, response)
 			return
 		}
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -36,10 +40,9 @@ func (h handler) ForumCreate(c *fasthttp.RequestCtx) {
 func (h handler) ForumGet(c *fasthttp.RequestCtx) {
 	forumInput := models.ForumInput{}
 	forumInput.Slug = c.UserValue("slug").(string)
-	forum, err := h.Service.GetForum(forumInput)
+	forum, err := h.Service.GetForum(requestContext(c), forumInput)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -48,18 +51,88 @@ func (h handler) ForumGet(c *fasthttp.RequestCtx) {
 	h.WriteResponse(c, fasthttp.StatusOK, response)
 }
 
+func (h handler) ForumGetChildren(c *fasthttp.RequestCtx) {
+	forumInput := models.ForumInput{}
+	forumInput.Slug = c.UserValue("slug").(string)
+	forums, err := h.Service.GetForumChildren(requestContext(c), forumInput)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(forums)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) ForumUpdate(c *fasthttp.RequestCtx) {
+	forumInput := &models.ForumUpdate{}
+	err := forumInput.UnmarshalJSON(c.PostBody())
+	if err != nil {
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+	if err = forumInput.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+	forumInput.Slug = c.UserValue("slug").(string)
+
+	forum, err := h.Service.UpdateForum(requestContext(c), *forumInput)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := forum.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+// ForumDelete removes a forum only when the confirm parameter repeats its
+// slug; with dry_run it reports what would be removed instead.
+func (h handler) ForumDelete(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	slug := c.UserValue("slug").(string)
+	dryRun := p.bool("dry_run")
+	confirm := string(c.QueryArgs().Peek("confirm"))
+	p.v.Check(dryRun || strings.EqualFold(confirm, slug), "confirm", "must repeat the forum slug unless dry_run is set")
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	deletion, err := h.Service.DeleteForum(requestContext(c), models.ForumInput{Slug: slug}, dryRun)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := deletion.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
 func (h handler) ForumGetThreads(c *fasthttp.RequestCtx) {
+	p := newParams(c)
 	input := models.ForumGetThreads{
-		Slug:  c.UserValue("slug").(string),
-		Limit: c.QueryArgs().GetUintOrZero("limit"),
-		Since: string(c.QueryArgs().Peek("since")),
-		Desc:  getBool("desc", c.QueryArgs()),
+		Slug:        c.UserValue("slug").(string),
+		Limit:       p.uint("limit"),
+		Since:       p.time("since"),
+		Desc:        p.bool("desc"),
+		Archived:    p.bool("archived"),
+		Viewer:      p.nickname("viewer"),
+		Tags:        p.tags("tags"),
+		Descendants: p.bool("descendants"),
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
 	}
 
-	threads, err := h.Service.GetForumThreads(input)
+	threads, err := h.Service.GetForumThreads(requestContext(c), input)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -70,17 +143,21 @@ func (h handler) ForumGetThreads(c *fasthttp.RequestCtx) {
 }
 
 func (h handler) ForumGetUsers(c *fasthttp.RequestCtx) {
+	p := newParams(c)
 	input := models.ForumGetUsers{
 		Slug:  c.UserValue("slug").(string),
-		Limit: c.QueryArgs().GetUintOrZero("limit"),
-		Since: string(c.QueryArgs().Peek("since")),
-		Desc:  getBool("desc", c.QueryArgs()),
+		Limit: p.uint("limit"),
+		Since: p.nickname("since"),
+		Desc:  p.bool("desc"),
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
 	}
 
-	users, err := h.Service.GetForumUsers(input)
+	users, err := h.Service.GetForumUsers(requestContext(c), input)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
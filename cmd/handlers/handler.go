@@ -1,14 +1,9 @@
 package handlers
 
 import (
-	"encoding/json"
-	"errors"
+	"github.com/EgorAist/TP_DB_project/internal/logger"
 	"github.com/EgorAist/TP_DB_project/internal/models"
 	"github.com/EgorAist/TP_DB_project/internal/services"
-	"github.com/EgorAist/TP_DB_project/internal/storages/forumStorage"
-	"github.com/EgorAist/TP_DB_project/internal/storages/postStorage"
-	"github.com/EgorAist/TP_DB_project/internal/storages/threadStorage"
-	"github.com/EgorAist/TP_DB_project/internal/storages/userStorage"
 	"github.com/valyala/fasthttp"
 	"strconv"
 )
@@ -18,40 +13,67 @@ type Handler interface {
 	ForumGet(c *fasthttp.RequestCtx)
 	ForumGetThreads(c *fasthttp.RequestCtx)
 	ForumGetUsers(c *fasthttp.RequestCtx)
+	ForumGetChildren(c *fasthttp.RequestCtx)
+	ForumUpdate(c *fasthttp.RequestCtx)
+	ForumDelete(c *fasthttp.RequestCtx)
 
 	ThreadCreate(c *fasthttp.RequestCtx)
 	ThreadVote(c *fasthttp.RequestCtx)
 	ThreadGet(c *fasthttp.RequestCtx)
 	ThreadUpdate(c *fasthttp.RequestCtx)
+	ThreadHistory(c *fasthttp.RequestCtx)
+	ThreadMarkRead(c *fasthttp.RequestCtx)
 	ThreadGetPosts(c *fasthttp.RequestCtx)
+	ThreadSetState(c *fasthttp.RequestCtx)
+	ThreadDelete(c *fasthttp.RequestCtx)
+
+	TagGetThreads(c *fasthttp.RequestCtx)
+	TagsGet(c *fasthttp.RequestCtx)
 
 	PostsCreate(c *fasthttp.RequestCtx)
 	PostGet(c *fasthttp.RequestCtx)
 	PostUpdate(c *fasthttp.RequestCtx)
+	PostDelete(c *fasthttp.RequestCtx)
+	PostHistory(c *fasthttp.RequestCtx)
+	PostVote(c *fasthttp.RequestCtx)
+	PostReact(c *fasthttp.RequestCtx)
+	PostUnreact(c *fasthttp.RequestCtx)
 
 	UserCreate(c *fasthttp.RequestCtx)
 	UserGet(c *fasthttp.RequestCtx)
 	UserUpdate(c *fasthttp.RequestCtx)
+	UserRename(c *fasthttp.RequestCtx)
+	UserDelete(c *fasthttp.RequestCtx)
+	UserExport(c *fasthttp.RequestCtx)
+	UserGetThreads(c *fasthttp.RequestCtx)
+	UserGetPosts(c *fasthttp.RequestCtx)
+	UserMentions(c *fasthttp.RequestCtx)
+
+	Search(c *fasthttp.RequestCtx)
+
+	ThreadSubscribe(c *fasthttp.RequestCtx)
+	ThreadUnsubscribe(c *fasthttp.RequestCtx)
+	ForumSubscribe(c *fasthttp.RequestCtx)
+	ForumUnsubscribe(c *fasthttp.RequestCtx)
+	UserNotifications(c *fasthttp.RequestCtx)
+	UserNotificationsRead(c *fasthttp.RequestCtx)
 
 	Clear(c *fasthttp.RequestCtx)
 	Status(c *fasthttp.RequestCtx)
+
+	AdminSlowQueries(c *fasthttp.RequestCtx)
+	AdminPostPurge(c *fasthttp.RequestCtx)
 }
 
 type handler struct {
 	Service services.Service
-	Forums forumStorage.Storage
-	Users userStorage.Storage
-	Threads threadStorage.Storage
-	Posts postStorage.Storage
+	log     *logger.Logger
 }
 
-func NewHandler(Service services.Service, Forums forumStorage.Storage, Users userStorage.Storage, Threads threadStorage.Storage, Posts postStorage.Storage) *handler {
+func NewHandler(Service services.Service, log *logger.Logger) *handler {
 	return &handler{
 		Service: Service,
-		Forums: Forums,
-		Users: Users,
-		Threads: Threads,
-		Posts: Posts,
+		log:     log,
 	}
 }
 
@@ -61,33 +83,6 @@ func (h handler) WriteResponse(c *fasthttp.RequestCtx, status int, body []byte)
 	c.Write(body)
 }
 
-func (h handler) ConvertError(someError error) (status int, body []byte, err error) {
-	Error, ok := someError.(models.Error)
-	if !ok {
-		return status, body, errors.New("it is not server error")
-	}
-
-	body, err = json.Marshal(Error)
-	if err != nil {
-		return status, body, err
-	}
-
-	status, err = strconv.Atoi(Error.Code)
-	if err != nil {
-		return status, body, err
-	}
-
-	return status, body, nil
-}
-
-func getBool(k string, args *fasthttp.Args) bool {
-	v := args.Peek(k)
-	if v != nil && v[0] == 't' {
-		return true
-	}
-	return false
-}
-
 func SlagOrID(c *fasthttp.RequestCtx) (output models.ThreadInput) {
 	slagOrID := c.UserValue("slug_or_id").(string)
 
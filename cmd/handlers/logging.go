@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/EgorAist/TP_DB_project/internal/logger"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	requestIDHeader  = "X-Request-ID"
+	requestLoggerKey = "requestLogger"
+
+	maxRequestIDLength = 128
+)
+
+// WithLogging tags the request with an ID, taken from X-Request-ID or
+// generated, echoes it in the response and logs the request once served.
+// The logger of the request carries the ID and is passed down to services
+// and storages through the request context (see WithTimeout).
+func WithLogging(log *logger.Logger, route string, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		id := string(c.Request.Header.Peek(requestIDHeader))
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Response.Header.Set(requestIDHeader, id)
+
+		requestLog := log.With("request_id", id, "route", route)
+		c.SetUserValue(requestLoggerKey, requestLog)
+
+		started := time.Now()
+		next(c)
+
+		requestLog.Info("request",
+			"method", string(c.Method()),
+			"path", string(c.Path()),
+			"status", c.Response.StatusCode(),
+			"duration", time.Since(started),
+		)
+	}
+}
+
+func requestLogger(c *fasthttp.RequestCtx) (*logger.Logger, bool) {
+	log, ok := c.UserValue(requestLoggerKey).(*logger.Logger)
+	return log, ok
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/EgorAist/TP_DB_project/internal/metrics"
+	"github.com/valyala/fasthttp"
+)
+
+var (
+	requestsTotal = metrics.NewCounterVec("http_requests_total",
+		"Requests served, by route, method and status code.", "route", "method", "status")
+	requestDuration = metrics.NewHistogramVec("http_request_duration_seconds",
+		"Time spent serving a request, by route.", metrics.DefaultBuckets, "route")
+)
+
+// WithMetrics counts the requests of route and observes their latency.
+func WithMetrics(route string, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		started := time.Now()
+		next(c)
+		requestDuration.Observe(time.Since(started).Seconds(), route)
+		requestsTotal.Inc(route, string(c.Method()), strconv.Itoa(c.Response.StatusCode()))
+	}
+}
+
+// Metrics serves every metric in the Prometheus text format.
+func Metrics(c *fasthttp.RequestCtx) {
+	c.SetContentType("text/plain; version=0.0.4; charset=utf-8")
+	c.SetStatusCode(fasthttp.StatusOK)
+	_ = metrics.Default.Write(c)
+}
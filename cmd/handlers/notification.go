@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/valyala/fasthttp"
+)
+
+func (h handler) ThreadSubscribe(c *fasthttp.RequestCtx) {
+	input := &models.Subscription{}
+	err := input.UnmarshalJSON(c.PostBody())
+	if err != nil {
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+	if err = input.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	subscription, err := h.Service.SubscribeThread(requestContext(c), SlagOrID(c), *input)
+	h.writeSubscription(c, subscription, err)
+}
+
+// ThreadUnsubscribe takes the user in the nickname query parameter off the
+// subscribers of the thread.
+func (h handler) ThreadUnsubscribe(c *fasthttp.RequestCtx) {
+	input := models.Subscription{User: string(c.QueryArgs().Peek("nickname"))}
+	if err := input.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	subscription, err := h.Service.UnsubscribeThread(requestContext(c), SlagOrID(c), input)
+	h.writeSubscription(c, subscription, err)
+}
+
+func (h handler) ForumSubscribe(c *fasthttp.RequestCtx) {
+	input := &models.Subscription{}
+	err := input.UnmarshalJSON(c.PostBody())
+	if err != nil {
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+	if err = input.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+	input.Forum = c.UserValue("slug").(string)
+
+	subscription, err := h.Service.SubscribeForum(requestContext(c), *input)
+	h.writeSubscription(c, subscription, err)
+}
+
+func (h handler) ForumUnsubscribe(c *fasthttp.RequestCtx) {
+	input := models.Subscription{
+		User:  string(c.QueryArgs().Peek("nickname")),
+		Forum: c.UserValue("slug").(string),
+	}
+	if err := input.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	subscription, err := h.Service.UnsubscribeForum(requestContext(c), input)
+	h.writeSubscription(c, subscription, err)
+}
+
+func (h handler) writeSubscription(c *fasthttp.RequestCtx, subscription models.Subscription, err error) {
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := subscription.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) UserNotifications(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	input := models.UserGetNotifications{
+		Nickname: c.UserValue("nickname").(string),
+		Limit:    p.uint("limit"),
+		Since:    p.uint("since"),
+		Unread:   p.bool("unread"),
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	notifications, err := h.Service.GetNotifications(requestContext(c), input)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := notifications.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) UserNotificationsRead(c *fasthttp.RequestCtx) {
+	input := &models.NotificationsRead{}
+	err := input.UnmarshalJSON(c.PostBody())
+	if err != nil {
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+	if err = input.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+	input.Nickname = c.UserValue("nickname").(string)
+
+	unread, err := h.Service.MarkNotificationsRead(requestContext(c), *input)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := unread.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
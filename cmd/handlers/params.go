@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/valyala/fasthttp"
+)
+
+// params reads path and query parameters of a request, collecting every
+// malformed one so the client gets them all in a single 400.
+type params struct {
+	c *fasthttp.RequestCtx
+	v models.Validator
+}
+
+func newParams(c *fasthttp.RequestCtx) *params {
+	return &params{c: c}
+}
+
+// id reads a positive integer path parameter.
+func (p *params) id(name string) int {
+	value, _ := p.c.UserValue(name).(string)
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		p.v.Add(name, "must be a positive integer")
+		return 0
+	}
+	return id
+}
+
+func (p *params) uint(name string) int {
+	value := p.c.QueryArgs().Peek(name)
+	if value == nil {
+		return 0
+	}
+
+	n, err := strconv.Atoi(string(value))
+	if err != nil || n < 0 {
+		p.v.Add(name, "must be a non-negative integer")
+		return 0
+	}
+	return n
+}
+
+func (p *params) bool(name string) bool {
+	value := p.c.QueryArgs().Peek(name)
+	if value == nil {
+		return false
+	}
+
+	b, err := strconv.ParseBool(string(value))
+	if err != nil {
+		p.v.Add(name, "must be true or false")
+		return false
+	}
+	return b
+}
+
+func (p *params) time(name string) string {
+	value := string(p.c.QueryArgs().Peek(name))
+	if value == "" {
+		return ""
+	}
+
+	if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+		p.v.Add(name, "must be an RFC 3339 timestamp")
+	}
+	return value
+}
+
+func (p *params) nickname(name string) string {
+	value := string(p.c.QueryArgs().Peek(name))
+	if value != "" {
+		p.v.Nickname(name, value)
+	}
+	return value
+}
+
+// oneOf reads a parameter that must be empty or one of allowed.
+func (p *params) oneOf(name string, allowed ...string) string {
+	value := string(p.c.QueryArgs().Peek(name))
+	if value == "" {
+		return ""
+	}
+
+	for _, a := range allowed {
+		if value == a {
+			return value
+		}
+	}
+	p.v.Add(name, "must be one of "+strings.Join(allowed, ", "))
+	return value
+}
+
+// list reads a comma separated parameter whose items must be in allowed.
+func (p *params) list(name string, allowed ...string) []string {
+	value := string(p.c.QueryArgs().Peek(name))
+	if value == "" {
+		return nil
+	}
+
+	items := strings.Split(value, ",")
+	for _, item := range items {
+		found := false
+		for _, a := range allowed {
+			found = found || item == a
+		}
+		if !found {
+			p.v.Add(name, "items must be among "+strings.Join(allowed, ", "))
+			break
+		}
+	}
+	return items
+}
+
+// tags reads a comma separated list of thread tags.
+func (p *params) tags(name string) []string {
+	value := string(p.c.QueryArgs().Peek(name))
+	if value == "" {
+		return nil
+	}
+
+	tags := strings.Split(value, ",")
+	p.v.Tags(name, tags)
+	return tags
+}
+
+// tag reads a single thread tag from the path.
+func (p *params) tag(name string) string {
+	value, _ := p.c.UserValue(name).(string)
+	p.v.Tags(name, []string{value})
+	return value
+}
+
+func (p *params) err() error {
+	return p.v.Err()
+}
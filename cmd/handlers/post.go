@@ -4,59 +4,25 @@ import (
 	"encoding/json"
 	"github.com/EgorAist/TP_DB_project/internal/models"
 	"github.com/valyala/fasthttp"
-	"log"
-	"net/http"
-	"strconv"
-	"time"
+	"strings"
 )
 
 
 func (h handler) PostsCreate(c *fasthttp.RequestCtx) {
 	postsInput := make([]models.PostCreate, 0)
-	threadInput := models.ThreadInput{}
 	err := json.Unmarshal(c.PostBody(), &postsInput)
 	if err != nil {
-		log.Println(err)
+		h.WriteError(c, models.NewMalformed(err))
 		return
 	}
-
-	slugOrID := SlagOrID(c)
-	threadInput.ThreadID = slugOrID.ThreadID
-	threadInput.Slug = slugOrID.Slug
-
-	posts := make([]models.Post, 0)
-
-	forum, err := h.Threads.GetForumByThread(&threadInput)
-	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+	if err = models.ValidatePosts(postsInput); err != nil {
+		h.WriteError(c, err)
 		return
 	}
 
-	if len(postsInput) == 0 {
-		response, _ := json.Marshal(posts)
-		h.WriteResponse(c, fasthttp.StatusCreated, response)
-		return
-	}
-
-	creator := postsInput[0].Author
-	created := time.Now().Format(time.RFC3339Nano)
-	posts, err = h.Posts.CreatePosts(threadInput, forum, created, postsInput)
-
+	posts, err := h.Service.CreatePosts(requestContext(c), SlagOrID(c), postsInput)
 	if err != nil {
-		_, errUser := h.Users.GetUserByNickname(creator)
-		if errUser != nil {
-			_, respErr, _ := h.ConvertError(errUser)
-			h.WriteResponse(c, http.StatusNotFound, respErr)
-			return
-		}
-		if err.Error() == "409" {
-			status, respErr, _ := h.ConvertError(err)
-			h.WriteResponse(c, status, respErr)
-			return
-		}
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -67,12 +33,17 @@ func (h handler) PostsCreate(c *fasthttp.RequestCtx) {
 }
 
 func (h handler) PostGet(c *fasthttp.RequestCtx) {
-	id, _ := strconv.Atoi(c.UserValue("id").(string))
-	related := c.QueryArgs().Peek("related")
-	post, err := h.Service.GetPost(id, string(related))
+	p := newParams(c)
+	id := p.id("id")
+	related := p.list("related", "user", "forum", "thread", "history")
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	post, err := h.Service.GetPost(requestContext(c), id, strings.Join(related, ","))
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -84,19 +55,27 @@ func (h handler) PostGet(c *fasthttp.RequestCtx) {
 
 func (h handler) PostUpdate(c *fasthttp.RequestCtx) {
 	postInput := &models.PostUpdate{}
-	id, _ := strconv.Atoi(c.UserValue("id").(string))
-	postInput.ID = int(id)
+	p := newParams(c)
+	id := p.id("id")
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
 
 	err := postInput.UnmarshalJSON(c.PostBody())
 	if err != nil {
-		log.Println(err)
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+	postInput.ID = id
+	if err = postInput.Validate(); err != nil {
+		h.WriteError(c, err)
 		return
 	}
 
-	post, err := h.Service.UpdatePost(*postInput)
+	post, err := h.Service.UpdatePost(requestContext(c), *postInput)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -106,3 +85,135 @@ func (h handler) PostUpdate(c *fasthttp.RequestCtx) {
 	return
 }
 
+func (h handler) PostDelete(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	id := p.id("id")
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	post, err := h.Service.DeletePost(requestContext(c), id)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := post.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) PostHistory(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	input := models.PostGetHistory{
+		ID:    p.id("id"),
+		Limit: p.uint("limit"),
+		Desc:  p.bool("desc"),
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	revisions, err := h.Service.GetPostHistory(requestContext(c), input)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(revisions)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) PostVote(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	id := p.id("id")
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	voteInput := &models.PostVote{}
+	err := voteInput.UnmarshalJSON(c.PostBody())
+	if err != nil {
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+	if err = voteInput.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+	voteInput.Post = id
+
+	post, err := h.Service.PostVote(requestContext(c), *voteInput)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := post.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) PostReact(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	id := p.id("id")
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	reactionInput := &models.PostReaction{}
+	err := reactionInput.UnmarshalJSON(c.PostBody())
+	if err != nil {
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+	if err = reactionInput.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+	reactionInput.Post = id
+
+	post, err := h.Service.AddPostReaction(requestContext(c), *reactionInput)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := post.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+// PostUnreact takes a reaction back; the user and the reaction come in the
+// nickname and reaction query parameters.
+func (h handler) PostUnreact(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	reactionInput := models.PostReaction{
+		Post:     p.id("id"),
+		User:     string(c.QueryArgs().Peek("nickname")),
+		Reaction: string(c.QueryArgs().Peek("reaction")),
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+	if err := reactionInput.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	post, err := h.Service.RemovePostReaction(requestContext(c), reactionInput)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := post.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
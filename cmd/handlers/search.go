@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/valyala/fasthttp"
+)
+
+func (h handler) Search(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	input := models.SearchInput{
+		Query:  string(c.QueryArgs().Peek("q")),
+		Forum:  string(c.QueryArgs().Peek("forum")),
+		Thread: p.uint("thread"),
+		Author: p.nickname("author"),
+		Since:  p.time("since"),
+		Until:  p.time("until"),
+		Kind:   p.oneOf("type", models.SearchPost, models.SearchThread),
+		Limit:  p.uint("limit"),
+	}
+	p.v.Check(input.Query != "", "q", "is required")
+	p.v.Check(input.Limit <= models.MaxSearchLimit, "limit", fmt.Sprintf("must be at most %d", models.MaxSearchLimit))
+	if cursor := string(c.QueryArgs().Peek("cursor")); cursor != "" {
+		after, err := models.ParseSearchCursor(cursor)
+		if err != nil {
+			p.v.Add("cursor", "is not a cursor of a previous page")
+		}
+		input.After = &after
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	result, err := h.Service.Search(requestContext(c), input)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := result.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
@@ -2,11 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"github.com/EgorAist/TP_DB_project/internal/models"
 	"github.com/valyala/fasthttp"
 )
 
 func (h handler) Clear(c *fasthttp.RequestCtx) {
-	h.Service.Clear()
+	h.Service.Clear(requestContext(c))
 
 	c.SetContentType("application/json")
 	c.SetStatusCode(fasthttp.StatusOK)
@@ -14,7 +15,7 @@ func (h handler) Clear(c *fasthttp.RequestCtx) {
 }
 
 func (h handler) Status(c *fasthttp.RequestCtx) {
-	status := h.Service.Status()
+	status := h.Service.Status(requestContext(c))
 
 	response, _ := json.Marshal(status)
 
@@ -22,3 +23,29 @@ func (h handler) Status(c *fasthttp.RequestCtx) {
 	return
 }
 
+func (h handler) AdminSlowQueries(c *fasthttp.RequestCtx) {
+	queries := h.Service.SlowQueries(requestContext(c))
+
+	response, _ := models.SlowQueries(queries).MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) AdminPostPurge(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	id := p.id("id")
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	purged, err := h.Service.PurgePostSubtree(requestContext(c), id)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := purged.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
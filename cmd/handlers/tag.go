@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/valyala/fasthttp"
+)
+
+func (h handler) TagGetThreads(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	input := models.TagGetThreads{
+		Tag:   p.tag("tag"),
+		Limit: p.uint("limit"),
+		Since: p.time("since"),
+		Desc:  p.bool("desc"),
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	threads, err := h.Service.GetTagThreads(requestContext(c), input)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(threads)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+// TagsGet lists the tag dictionary, optionally only the tags starting with
+// the prefix parameter.
+func (h handler) TagsGet(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	input := models.TagsGet{
+		Prefix: string(c.QueryArgs().Peek("prefix")),
+		Limit:  p.uint("limit"),
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	tags, err := h.Service.GetTags(requestContext(c), input)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(tags)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
@@ -2,30 +2,33 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/EgorAist/TP_DB_project/internal/models"
 	"github.com/valyala/fasthttp"
-	"log"
 )
 
 func (h handler) ThreadCreate(c *fasthttp.RequestCtx) {
 	threadInput := &models.Thread{}
 	err :=threadInput.UnmarshalJSON(c.PostBody())
 	if err != nil {
-		log.Println(err)
+		h.WriteError(c, models.NewMalformed(err))
 		return
 	}
 
 	threadInput.Forum = c.UserValue("slug").(string)
+	if err = threadInput.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
 
-	thread, err := h.Service.CreateThread(*threadInput)
+	thread, err := h.Service.CreateThread(requestContext(c), *threadInput)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		if status == fasthttp.StatusConflict {
+		if errors.Is(err, models.ErrConflict) {
 			response, _ := thread.MarshalJSON()
-			h.WriteResponse(c, status, response)
+			h.WriteResponse(c, fasthttp.StatusConflict, response)
 			return
 		}
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -40,16 +43,20 @@ func (h handler) ThreadVote(c *fasthttp.RequestCtx) {
 
 	err := voteInput.UnmarshalJSON(c.PostBody())
 	if err != nil {
-		log.Println(err)
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+
+	if err = voteInput.Validate(); err != nil {
+		h.WriteError(c, err)
 		return
 	}
 
 	voteInput.Thread = SlagOrID(c)
 
-	thread, err := h.Service.ThreadVote(*voteInput)
+	thread, err := h.Service.ThreadVote(requestContext(c), *voteInput)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -61,11 +68,16 @@ func (h handler) ThreadVote(c *fasthttp.RequestCtx) {
 
 func (h handler) ThreadGet(c *fasthttp.RequestCtx) {
 	threadInput := SlagOrID(c)
+	p := newParams(c)
+	viewer := p.nickname("viewer")
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
 
-	thread, err := h.Service.GetThread(threadInput)
+	thread, err := h.Service.GetThread(requestContext(c), threadInput, viewer)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -75,11 +87,41 @@ func (h handler) ThreadGet(c *fasthttp.RequestCtx) {
 	return
 }
 
+// ThreadMarkRead records that the user has read the thread up to the post in
+// the body, or all of it.
+func (h handler) ThreadMarkRead(c *fasthttp.RequestCtx) {
+	input := &models.ReadMarker{}
+	err := input.UnmarshalJSON(c.PostBody())
+	if err != nil {
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+	if err = input.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	marker, err := h.Service.MarkThreadRead(requestContext(c), SlagOrID(c), *input)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := marker.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
 func (h handler) ThreadUpdate(c *fasthttp.RequestCtx) {
 	threadInput := &models.ThreadUpdate{}
 	err := threadInput.UnmarshalJSON(c.PostBody())
 	if err != nil {
-		log.Println(err)
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+
+	if err = threadInput.Validate(); err != nil {
+		h.WriteError(c, err)
 		return
 	}
 
@@ -88,10 +130,9 @@ func (h handler) ThreadUpdate(c *fasthttp.RequestCtx) {
 	threadInput.ThreadID = slagOrID.ThreadID
 	threadInput.Slug = slagOrID.Slug
 
-	thread, err := h.Service.UpdateThread(*threadInput)
+	thread, err := h.Service.UpdateThread(requestContext(c), *threadInput)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -102,21 +143,25 @@ func (h handler) ThreadUpdate(c *fasthttp.RequestCtx) {
 }
 
 func (h handler) ThreadGetPosts(c *fasthttp.RequestCtx) {
+	p := newParams(c)
 	threadInput := models.ThreadGetPosts{
-		Limit:    c.QueryArgs().GetUintOrZero("limit"),
-		Since:    c.QueryArgs().GetUintOrZero("since"),
-		Sort:     string(c.QueryArgs().Peek("sort")),
-		Desc:     getBool("desc", c.QueryArgs()),
+		Limit:    p.uint("limit"),
+		Since:    p.uint("since"),
+		Sort:     p.oneOf("sort", "flat", "tree", "parent_tree", "top"),
+		Desc:     p.bool("desc"),
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
 	}
 
 	slugOrID := SlagOrID(c)
 	threadInput.ThreadID = slugOrID.ThreadID
 	threadInput.Slug = slugOrID.Slug
 
-	posts, err := h.Service.GetThreadPosts(threadInput)
+	posts, err := h.Service.GetThreadPosts(requestContext(c), threadInput)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -126,3 +171,63 @@ func (h handler) ThreadGetPosts(c *fasthttp.RequestCtx) {
 	return
 }
 
+func (h handler) ThreadSetState(c *fasthttp.RequestCtx) {
+	stateInput := &models.ThreadState{}
+	err := stateInput.UnmarshalJSON(c.PostBody())
+	if err != nil {
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+
+	if err = stateInput.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	stateInput.ThreadInput = SlagOrID(c)
+
+	thread, err := h.Service.SetThreadState(requestContext(c), *stateInput)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := thread.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) ThreadDelete(c *fasthttp.RequestCtx) {
+	thread, err := h.Service.DeleteThread(requestContext(c), SlagOrID(c))
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := thread.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) ThreadHistory(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	input := models.ThreadGetHistory{
+		ThreadInput: SlagOrID(c),
+		Limit:       p.uint("limit"),
+		Desc:        p.bool("desc"),
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	revisions, err := h.Service.GetThreadHistory(requestContext(c), input)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(revisions)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
@@ -2,9 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/EgorAist/TP_DB_project/internal/models"
 	"github.com/valyala/fasthttp"
-	"log"
+	"net/url"
 )
 
 func (h handler) UserCreate(c *fasthttp.RequestCtx) {
@@ -12,21 +13,24 @@ func (h handler) UserCreate(c *fasthttp.RequestCtx) {
 	userInput.Nickname = c.UserValue("nickname").(string)
 	err := userInput.UnmarshalJSON(c.PostBody())
 	if err != nil {
-		log.Println(err)
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+	if err = userInput.Validate(); err != nil {
+		h.WriteError(c, err)
 		return
 	}
 
-	user, err := h.Service.CreateUser(*userInput)
+	user, err := h.Service.CreateUser(requestContext(c), *userInput)
 
 
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		if status == fasthttp.StatusConflict {
+		if errors.Is(err, models.ErrConflict) {
 			response, _ := json.Marshal(user)
-			h.WriteResponse(c, status, response)
+			h.WriteResponse(c, fasthttp.StatusConflict, response)
 			return
 		}
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -39,10 +43,17 @@ func (h handler) UserCreate(c *fasthttp.RequestCtx) {
 func (h handler) UserGet(c *fasthttp.RequestCtx) {
 	nickname := c.UserValue("nickname").(string)
 
-	user, err := h.Service.GetUser(nickname)
+	user, err := h.Service.GetUser(requestContext(c), nickname)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		// A reserved old nickname redirects to the new profile; not permanently,
+		// as the nickname is freed once the reservation runs out.
+		if errors.Is(err, models.ErrNotFound) {
+			if current, aliasErr := h.Service.ResolveNickname(requestContext(c), nickname); aliasErr == nil {
+				c.Redirect("/api/user/"+url.PathEscape(current)+"/profile", fasthttp.StatusFound)
+				return
+			}
+		}
+		h.WriteError(c, err)
 		return
 	}
 
@@ -57,14 +68,17 @@ func (h handler) UserUpdate(c *fasthttp.RequestCtx) {
 	userInput.Nickname = c.UserValue("nickname").(string)
 	err := userInput.UnmarshalJSON(c.PostBody())
 	if err != nil {
-		log.Println(err)
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+	if err = userInput.ValidateUpdate(); err != nil {
+		h.WriteError(c, err)
 		return
 	}
 
-	user, err := h.Service.UpdateUser(*userInput)
+	user, err := h.Service.UpdateUser(requestContext(c), *userInput)
 	if err != nil {
-		status, respErr, _ := h.ConvertError(err)
-		h.WriteResponse(c, status, respErr)
+		h.WriteError(c, err)
 		return
 	}
 
@@ -74,3 +88,136 @@ func (h handler) UserUpdate(c *fasthttp.RequestCtx) {
 	return
 }
 
+func (h handler) UserRename(c *fasthttp.RequestCtx) {
+	renameInput := &models.UserRename{}
+	err := renameInput.UnmarshalJSON(c.PostBody())
+	if err != nil {
+		h.WriteError(c, models.NewMalformed(err))
+		return
+	}
+	if err = renameInput.Validate(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+	renameInput.Nickname = c.UserValue("nickname").(string)
+
+	user, err := h.Service.RenameUser(requestContext(c), *renameInput)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := user.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+// UserDelete anonymizes the user, and with mode=purge removes their content
+// as well.
+func (h handler) UserDelete(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	mode := p.oneOf("mode", models.DeleteAnonymize, models.DeletePurge)
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+	if mode == "" {
+		mode = models.DeleteAnonymize
+	}
+
+	deletion, err := h.Service.DeleteUser(requestContext(c), c.UserValue("nickname").(string), mode)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := deletion.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) UserExport(c *fasthttp.RequestCtx) {
+	export, err := h.Service.ExportUser(requestContext(c), c.UserValue("nickname").(string))
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := export.MarshalJSON()
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) UserGetThreads(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	input := models.UserGetThreads{
+		Nickname: c.UserValue("nickname").(string),
+		Forum:    string(c.QueryArgs().Peek("forum")),
+		Limit:    p.uint("limit"),
+		Since:    p.time("since"),
+		Desc:     p.bool("desc"),
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	threads, err := h.Service.GetUserThreads(requestContext(c), input)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(threads)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) UserGetPosts(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	input := models.UserGetPosts{
+		Nickname: c.UserValue("nickname").(string),
+		Forum:    string(c.QueryArgs().Peek("forum")),
+		Limit:    p.uint("limit"),
+		Since:    p.uint("since"),
+		Desc:     p.bool("desc"),
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	posts, err := h.Service.GetUserPosts(requestContext(c), input)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(posts)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
+
+func (h handler) UserMentions(c *fasthttp.RequestCtx) {
+	p := newParams(c)
+	input := models.UserGetMentions{
+		Nickname: c.UserValue("nickname").(string),
+		Limit:    p.uint("limit"),
+		Since:    p.uint("since"),
+		Desc:     p.bool("desc"),
+	}
+	if err := p.err(); err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	mentions, err := h.Service.GetUserMentions(requestContext(c), input)
+	if err != nil {
+		h.WriteError(c, err)
+		return
+	}
+
+	response, _ := json.Marshal(mentions)
+
+	h.WriteResponse(c, fasthttp.StatusOK, response)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/EgorAist/TP_DB_project/internal/config"
+	"github.com/EgorAist/TP_DB_project/internal/logger"
+	"github.com/EgorAist/TP_DB_project/internal/migrations"
+	"github.com/jackc/pgx"
+)
+
+const migrateUsage = "usage: TP_DB_project [flags] migrate up | down [steps] | status | baseline <version>"
+
+// migrate runs the migrate subcommand.
+func migrate(cfg config.Config, log *logger.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	poolConfig, err := cfg.Database.PoolConfig()
+	if err != nil {
+		return err
+	}
+
+	pool, err := pgx.NewConnPool(poolConfig)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	migrator, err := migrations.NewMigrator(pool, log)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return errors.New(migrateUsage)
+			}
+		}
+		return migrator.Down(ctx, steps)
+	case "baseline":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return errors.New(migrateUsage)
+		}
+		return migrator.Baseline(ctx, version)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, applied)
+		}
+		return w.Flush()
+	}
+
+	return errors.New(migrateUsage)
+}
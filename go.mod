@@ -1,6 +1,6 @@
 module github.com/EgorAist/TP_DB_project
 
-go 1.15
+go 1.16
 
 require (
 	github.com/alecthomas/template v0.0.0-20190718012654-fb15b899a751
@@ -15,4 +15,5 @@ require (
 	github.com/swaggo/echo-swagger v1.0.0
 	github.com/swaggo/swag v1.6.9
 	github.com/valyala/fasthttp v1.17.0
+	gopkg.in/yaml.v2 v2.2.8
 )
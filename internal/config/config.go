@@ -0,0 +1,447 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EgorAist/TP_DB_project/internal/logger"
+	"github.com/jackc/pgx"
+	"gopkg.in/yaml.v2"
+)
+
+// Config is the complete runtime configuration of the server.
+//
+// Values are resolved in the following order, each step overriding the
+// previous one: built-in defaults, the optional YAML file, environment
+// variables and finally command line flags.
+type Config struct {
+	Server   Server   `yaml:"server"`
+	Database Database `yaml:"database"`
+	Features Features `yaml:"features"`
+	Users    Users    `yaml:"users"`
+	Log      Log      `yaml:"log"`
+}
+
+type Server struct {
+	Host         string        `yaml:"host"`
+	Port         int           `yaml:"port"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// ShutdownTimeout bounds how long in-flight requests are waited for
+	// after SIGINT or SIGTERM.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// RequestTimeout bounds the database work of a single request; zero
+	// disables it. RouteTimeouts overrides it per route, keyed by handler
+	// name (e.g. ThreadGetPosts).
+	RequestTimeout time.Duration            `yaml:"request_timeout"`
+	RouteTimeouts  map[string]time.Duration `yaml:"route_timeouts"`
+}
+
+type Database struct {
+	Host           string        `yaml:"host"`
+	Port           int           `yaml:"port"`
+	Name           string        `yaml:"name"`
+	User           string        `yaml:"user"`
+	Password       string        `yaml:"password"`
+	SSLMode        string        `yaml:"sslmode"`
+	MaxConnections int           `yaml:"max_connections"`
+	AcquireTimeout time.Duration `yaml:"acquire_timeout"`
+	// SlowQueryThreshold logs every query taking at least this long; zero
+	// disables the slow query log. ExplainSampleRate is the share of slow
+	// SELECTs whose plan is captured with EXPLAIN (ANALYZE, BUFFERS).
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"`
+	ExplainSampleRate  float64       `yaml:"explain_sample_rate"`
+	// Migrate applies pending schema migrations when the server starts.
+	Migrate bool `yaml:"migrate"`
+}
+
+type Log struct {
+	// Level is one of debug, info, warn and error.
+	Level string `yaml:"level"`
+	// Outputs lists where log lines are written: stdout, stderr or file paths.
+	Outputs []string `yaml:"outputs"`
+}
+
+type Users struct {
+	// NicknameAliasTTL is how long the old nickname of a renamed user stays
+	// reserved and redirects to the new one; zero frees it at once.
+	NicknameAliasTTL time.Duration `yaml:"nickname_alias_ttl"`
+	// UnknownMentions is what happens to a message mentioning a nickname
+	// nobody has: "reject" refuses it, "flag" accepts it and lists the
+	// nickname in the response.
+	UnknownMentions string `yaml:"unknown_mentions"`
+}
+
+// Modes of Users.UnknownMentions.
+const (
+	MentionsFlag   = "flag"
+	MentionsReject = "reject"
+)
+
+type Features struct {
+	// ServiceClear enables POST /api/service/clear, which truncates every table.
+	ServiceClear bool `yaml:"service_clear"`
+	// Admin enables the /api/admin endpoints, e.g. the captured slow queries.
+	Admin bool `yaml:"admin"`
+	// AdminToken is the bearer token the /api/admin endpoints require; it
+	// must be set when Admin is on.
+	AdminToken string `yaml:"admin_token"`
+}
+
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:            5000,
+			ShutdownTimeout: 30 * time.Second,
+			RequestTimeout:  30 * time.Second,
+		},
+		Database: Database{
+			Host:           "localhost",
+			Port:           5432,
+			SSLMode:        "disable",
+			MaxConnections: 2000,
+			Migrate:        true,
+		},
+		Features: Features{
+			ServiceClear: true,
+		},
+		Users: Users{
+			NicknameAliasTTL: 30 * 24 * time.Hour,
+			UnknownMentions:  MentionsFlag,
+		},
+		Log: Log{
+			Level:   "info",
+			Outputs: []string{"stdout"},
+		},
+	}
+}
+
+// Load builds the configuration from defaults, the file given by -config or
+// CONFIG_FILE, the environment and args (usually os.Args[1:]), and validates it.
+func Load(args []string) (Config, error) {
+	cfg, _, err := LoadArgs(args)
+	return cfg, err
+}
+
+// LoadArgs is Load that also returns the arguments left after the flags.
+func LoadArgs(args []string) (Config, []string, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("forum", flag.ContinueOnError)
+	file := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file")
+	flags := bindFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return cfg, nil, err
+	}
+
+	if *file != "" {
+		if err := loadFile(*file, &cfg); err != nil {
+			return cfg, nil, err
+		}
+	}
+
+	if err := loadEnv(&cfg); err != nil {
+		return cfg, nil, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		if apply, ok := flags[f.Name]; ok {
+			apply(&cfg)
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, nil, err
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: reading %s: %v", path, err)
+	}
+
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return fmt.Errorf("config: parsing %s: %v", path, err)
+	}
+
+	return nil
+}
+
+func loadEnv(cfg *Config) error {
+	var errs []string
+
+	str := func(key string, dst *string) {
+		if v, ok := os.LookupEnv(key); ok {
+			*dst = v
+		}
+	}
+	num := func(key string, dst *int) {
+		if v, ok := os.LookupEnv(key); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s=%q is not an integer", key, v))
+				return
+			}
+			*dst = n
+		}
+	}
+	dur := func(key string, dst *time.Duration) {
+		if v, ok := os.LookupEnv(key); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s=%q is not a duration", key, v))
+				return
+			}
+			*dst = d
+		}
+	}
+	fraction := func(key string, dst *float64) {
+		if v, ok := os.LookupEnv(key); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s=%q is not a number", key, v))
+				return
+			}
+			*dst = f
+		}
+	}
+	boolean := func(key string, dst *bool) {
+		if v, ok := os.LookupEnv(key); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s=%q is not a boolean", key, v))
+				return
+			}
+			*dst = b
+		}
+	}
+
+	str("HOST", &cfg.Server.Host)
+	num("PORT", &cfg.Server.Port)
+	dur("READ_TIMEOUT", &cfg.Server.ReadTimeout)
+	dur("WRITE_TIMEOUT", &cfg.Server.WriteTimeout)
+	dur("IDLE_TIMEOUT", &cfg.Server.IdleTimeout)
+	dur("SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout)
+	dur("REQUEST_TIMEOUT", &cfg.Server.RequestTimeout)
+	if v, ok := os.LookupEnv("ROUTE_TIMEOUTS"); ok {
+		timeouts, err := parseRouteTimeouts(v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("ROUTE_TIMEOUTS: %v", err))
+		} else {
+			cfg.Server.RouteTimeouts = timeouts
+		}
+	}
+
+	str("POSTGRES_HOST", &cfg.Database.Host)
+	num("POSTGRES_PORT", &cfg.Database.Port)
+	str("POSTGRES_DB", &cfg.Database.Name)
+	str("POSTGRES_USER", &cfg.Database.User)
+	str("POSTGRES_PASSWORD", &cfg.Database.Password)
+	str("POSTGRES_SSLMODE", &cfg.Database.SSLMode)
+	num("POSTGRES_MAX_CONNECTIONS", &cfg.Database.MaxConnections)
+	dur("POSTGRES_ACQUIRE_TIMEOUT", &cfg.Database.AcquireTimeout)
+	dur("SLOW_QUERY_THRESHOLD", &cfg.Database.SlowQueryThreshold)
+	fraction("EXPLAIN_SAMPLE_RATE", &cfg.Database.ExplainSampleRate)
+	boolean("MIGRATE_ON_START", &cfg.Database.Migrate)
+
+	boolean("FEATURE_SERVICE_CLEAR", &cfg.Features.ServiceClear)
+	boolean("FEATURE_ADMIN", &cfg.Features.Admin)
+	str("ADMIN_TOKEN", &cfg.Features.AdminToken)
+
+	dur("NICKNAME_ALIAS_TTL", &cfg.Users.NicknameAliasTTL)
+	str("UNKNOWN_MENTIONS", &cfg.Users.UnknownMentions)
+
+	str("LOG_LEVEL", &cfg.Log.Level)
+	if v, ok := os.LookupEnv("LOG_OUTPUTS"); ok {
+		cfg.Log.Outputs = splitList(v)
+	}
+
+	if len(errs) != 0 {
+		return errors.New("config: invalid environment: " + strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// parseRouteTimeouts parses "ThreadGetPosts=5s,PostsCreate=20s".
+func parseRouteTimeouts(value string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("%q is not route=duration", pair)
+		}
+
+		d, err := time.ParseDuration(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a duration", kv[1])
+		}
+		timeouts[strings.TrimSpace(kv[0])] = d
+	}
+
+	return timeouts, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// bindFlags registers the command line overrides and returns, per flag name,
+// a function copying the parsed value into a Config. Only flags that were
+// actually passed are applied, so defaults never hide file or env values.
+func bindFlags(fs *flag.FlagSet) map[string]func(*Config) {
+	host := fs.String("host", "", "address to listen on")
+	port := fs.Int("port", 0, "port to listen on")
+	shutdownTimeout := fs.Duration("shutdown-timeout", 0, "how long to wait for in-flight requests on shutdown")
+	dbHost := fs.String("db-host", "", "PostgreSQL host")
+	dbPort := fs.Int("db-port", 0, "PostgreSQL port")
+	dbName := fs.String("db-name", "", "PostgreSQL database")
+	dbUser := fs.String("db-user", "", "PostgreSQL user")
+	dbPassword := fs.String("db-password", "", "PostgreSQL password")
+	dbMaxConnections := fs.Int("db-max-connections", 0, "size of the connection pool")
+	serviceClear := fs.Bool("service-clear", false, "enable POST /api/service/clear")
+	slowQueryThreshold := fs.Duration("slow-query-threshold", 0, "log queries taking at least this long")
+	migrate := fs.Bool("migrate", false, "apply pending migrations on start")
+	admin := fs.Bool("admin", false, "enable the /api/admin endpoints")
+	logLevel := fs.String("log-level", "", "debug, info, warn or error")
+
+	return map[string]func(*Config){
+		"host":                 func(c *Config) { c.Server.Host = *host },
+		"port":                 func(c *Config) { c.Server.Port = *port },
+		"shutdown-timeout":     func(c *Config) { c.Server.ShutdownTimeout = *shutdownTimeout },
+		"db-host":              func(c *Config) { c.Database.Host = *dbHost },
+		"db-port":              func(c *Config) { c.Database.Port = *dbPort },
+		"db-name":              func(c *Config) { c.Database.Name = *dbName },
+		"db-user":              func(c *Config) { c.Database.User = *dbUser },
+		"db-password":          func(c *Config) { c.Database.Password = *dbPassword },
+		"db-max-connections":   func(c *Config) { c.Database.MaxConnections = *dbMaxConnections },
+		"service-clear":        func(c *Config) { c.Features.ServiceClear = *serviceClear },
+		"log-level":            func(c *Config) { c.Log.Level = *logLevel },
+		"slow-query-threshold": func(c *Config) { c.Database.SlowQueryThreshold = *slowQueryThreshold },
+		"admin":                func(c *Config) { c.Features.Admin = *admin },
+		"migrate":              func(c *Config) { c.Database.Migrate = *migrate },
+	}
+}
+
+// Validate reports every missing or out of range setting at once.
+func (c Config) Validate() error {
+	var errs []string
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Sprintf("server.port (PORT) must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 || c.Server.ShutdownTimeout < 0 {
+		errs = append(errs, "server timeouts must not be negative")
+	}
+	if c.Server.ShutdownTimeout == 0 {
+		errs = append(errs, "server.shutdown_timeout (SHUTDOWN_TIMEOUT) must be positive, or in-flight requests are aborted at once")
+	}
+	if c.Server.RequestTimeout < 0 {
+		errs = append(errs, "server.request_timeout (REQUEST_TIMEOUT) must not be negative")
+	}
+	for route, timeout := range c.Server.RouteTimeouts {
+		if timeout < 0 {
+			errs = append(errs, fmt.Sprintf("server.route_timeouts.%s must not be negative", route))
+		}
+	}
+
+	if c.Database.Host == "" {
+		errs = append(errs, "database.host (POSTGRES_HOST) is required")
+	}
+	if c.Database.Port <= 0 || c.Database.Port > 65535 {
+		errs = append(errs, fmt.Sprintf("database.port (POSTGRES_PORT) must be between 1 and 65535, got %d", c.Database.Port))
+	}
+	if c.Database.Name == "" {
+		errs = append(errs, "database.name (POSTGRES_DB) is required")
+	}
+	if c.Database.User == "" {
+		errs = append(errs, "database.user (POSTGRES_USER) is required")
+	}
+	if c.Database.MaxConnections <= 1 {
+		errs = append(errs, fmt.Sprintf("database.max_connections (POSTGRES_MAX_CONNECTIONS) must be greater than 1, got %d", c.Database.MaxConnections))
+	}
+	if c.Database.AcquireTimeout < 0 {
+		errs = append(errs, "database.acquire_timeout (POSTGRES_ACQUIRE_TIMEOUT) must not be negative")
+	}
+	if c.Database.SlowQueryThreshold < 0 {
+		errs = append(errs, "database.slow_query_threshold (SLOW_QUERY_THRESHOLD) must not be negative")
+	}
+	if c.Database.ExplainSampleRate < 0 || c.Database.ExplainSampleRate > 1 {
+		errs = append(errs, fmt.Sprintf("database.explain_sample_rate (EXPLAIN_SAMPLE_RATE) must be between 0 and 1, got %g", c.Database.ExplainSampleRate))
+	}
+
+	if c.Features.Admin && c.Features.AdminToken == "" {
+		errs = append(errs, "features.admin_token (ADMIN_TOKEN) is required when the admin endpoints are enabled")
+	}
+
+	if c.Users.NicknameAliasTTL < 0 {
+		errs = append(errs, "users.nickname_alias_ttl (NICKNAME_ALIAS_TTL) must not be negative")
+	}
+	if c.Users.UnknownMentions != MentionsFlag && c.Users.UnknownMentions != MentionsReject {
+		errs = append(errs, "users.unknown_mentions (UNKNOWN_MENTIONS) must be flag or reject")
+	}
+
+	if _, err := logger.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, "log.level (LOG_LEVEL) must be debug, info, warn or error")
+	}
+
+	if len(errs) != 0 {
+		return errors.New("config: " + strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// Timeout returns the request timeout configured for route.
+func (s Server) Timeout(route string) time.Duration {
+	if timeout, ok := s.RouteTimeouts[route]; ok {
+		return timeout
+	}
+	return s.RequestTimeout
+}
+
+func (s Server) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+func (d Database) PoolConfig() (pgx.ConnPoolConfig, error) {
+	connConfig, err := pgx.ParseConnectionString(fmt.Sprintf("host=%s port=%d dbname=%s user=%s password=%s sslmode=%s",
+		quote(d.Host), d.Port, quote(d.Name), quote(d.User), quote(d.Password), quote(d.SSLMode)))
+	if err != nil {
+		return pgx.ConnPoolConfig{}, fmt.Errorf("config: database: %v", err)
+	}
+
+	return pgx.ConnPoolConfig{
+		ConnConfig:     connConfig,
+		MaxConnections: d.MaxConnections,
+		AcquireTimeout: d.AcquireTimeout,
+	}, nil
+}
+
+func quote(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `'`, `\'`, -1)
+	return "'" + value + "'"
+}
@@ -0,0 +1,209 @@
+// Package logger writes leveled log lines as JSON objects, one per line:
+//
+//	{"time":"2020-11-20T15:04:05.123Z","level":"info","msg":"request","request_id":"5f2c...","status":200}
+//
+// Loggers carry fields added with With; the logger of a request, holding its
+// request ID, travels in the request context and is picked up with For.
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+// sink is shared by a logger and everything derived from it with With.
+type sink struct {
+	mu    sync.Mutex
+	out   io.Writer
+	level Level
+}
+
+type Logger struct {
+	sink   *sink
+	fields []byte
+}
+
+func New(out io.Writer, level Level) *Logger {
+	return &Logger{sink: &sink{out: out, level: level}}
+}
+
+// Open creates a logger writing to every output: "stdout", "stderr" or a
+// file path, which is appended to. The returned function closes the files.
+func Open(outputs []string, level Level) (*Logger, func() error, error) {
+	writers := make([]io.Writer, 0, len(outputs))
+	files := make([]*os.File, 0, len(outputs))
+	closeFiles := func() error {
+		var first error
+		for _, f := range files {
+			if err := f.Close(); err != nil && first == nil {
+				first = err
+			}
+		}
+		return first
+	}
+
+	for _, output := range outputs {
+		switch output {
+		case "stdout":
+			writers = append(writers, os.Stdout)
+		case "stderr":
+			writers = append(writers, os.Stderr)
+		default:
+			f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+			if err != nil {
+				_ = closeFiles()
+				return nil, nil, fmt.Errorf("logger: %v", err)
+			}
+			files = append(files, f)
+			writers = append(writers, f)
+		}
+	}
+
+	if len(writers) == 0 {
+		writers = append(writers, os.Stderr)
+	}
+
+	return New(io.MultiWriter(writers...), level), closeFiles, nil
+}
+
+// With returns a logger adding the key-value pairs to every line.
+func (l *Logger) With(keyvals ...interface{}) *Logger {
+	buf := bytes.NewBuffer(append([]byte(nil), l.fields...))
+	writeFields(buf, keyvals)
+	return &Logger{sink: l.sink, fields: buf.Bytes()}
+}
+
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.sink.level
+}
+
+func (l *Logger) Debug(msg string, keyvals ...interface{}) {
+	l.log(LevelDebug, msg, keyvals)
+}
+
+func (l *Logger) Info(msg string, keyvals ...interface{}) {
+	l.log(LevelInfo, msg, keyvals)
+}
+
+func (l *Logger) Warn(msg string, keyvals ...interface{}) {
+	l.log(LevelWarn, msg, keyvals)
+}
+
+func (l *Logger) Error(msg string, keyvals ...interface{}) {
+	l.log(LevelError, msg, keyvals)
+}
+
+func (l *Logger) Log(level Level, msg string, keyvals ...interface{}) {
+	l.log(level, msg, keyvals)
+}
+
+func (l *Logger) log(level Level, msg string, keyvals []interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(`{"time":`)
+	writeValue(buf, time.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeValue(buf, level.String())
+	buf.WriteString(`,"msg":`)
+	writeValue(buf, msg)
+	buf.Write(l.fields)
+	writeFields(buf, keyvals)
+	buf.WriteString("}\n")
+
+	l.sink.mu.Lock()
+	_, _ = l.sink.out.Write(buf.Bytes())
+	l.sink.mu.Unlock()
+}
+
+func writeFields(buf *bytes.Buffer, keyvals []interface{}) {
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		var value interface{} = "(missing)"
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+
+		buf.WriteByte(',')
+		writeValue(buf, key)
+		buf.WriteByte(':')
+		writeValue(buf, value)
+	}
+}
+
+func writeValue(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case error:
+		value = v.Error()
+	case time.Duration:
+		value = v.String()
+	case fmt.Stringer:
+		value = v.String()
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buf.Write(data)
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying l.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// For returns the logger carried by ctx, or l when there is none.
+func (l *Logger) For(ctx context.Context) *Logger {
+	if ctxLogger, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return ctxLogger
+	}
+	return l
+}
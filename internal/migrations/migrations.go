@@ -0,0 +1,264 @@
+// Package migrations keeps the database schema as numbered SQL files
+// embedded in the binary and applies them in order.
+//
+// Every change to the schema is a new pair of files in sql/:
+//
+//	NNNN_description.up.sql
+//	NNNN_description.down.sql
+//
+// Applied versions are recorded in schema_migrations. Each migration runs in
+// its own transaction, and a PostgreSQL advisory lock keeps two processes
+// from migrating the same database at once.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/EgorAist/TP_DB_project/internal/logger"
+	"github.com/jackc/pgx"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// lockID is the advisory lock key held while migrating.
+const lockID = 7316028311
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration together with the time it was applied, if it was.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load returns the embedded migrations ordered by version.
+func Load() ([]Migration, error) {
+	entries, err := files.ReadDir("sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migrations: unexpected file %s", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migrations: version %d is used by both %s and %s", version, m.Name, match[2])
+		}
+
+		data, err := files.ReadFile(path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migrations: %04d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+type Migrator struct {
+	pool       *pgx.ConnPool
+	log        *logger.Logger
+	migrations []Migration
+}
+
+func NewMigrator(pool *pgx.ConnPool, log *logger.Logger) (*Migrator, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		pool:       pool,
+		log:        log,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies every migration that has not been applied yet.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.locked(ctx, func(conn *pgx.Conn, applied map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(ctx, conn, migration.Up,
+				"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("migrations: applying %04d_%s: %v", migration.Version, migration.Name, err)
+			}
+			m.log.Info("migration applied", "version", migration.Version, "name", migration.Name)
+		}
+		return nil
+	})
+}
+
+// Down reverts the last steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.locked(ctx, func(conn *pgx.Conn, applied map[int]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migrations: %04d_%s cannot be reverted", migration.Version, migration.Name)
+			}
+
+			if err := m.apply(ctx, conn, migration.Down,
+				"DELETE FROM schema_migrations WHERE version = $1", migration.Version); err != nil {
+				return fmt.Errorf("migrations: reverting %04d_%s: %v", migration.Version, migration.Name, err)
+			}
+			m.log.Info("migration reverted", "version", migration.Version, "name", migration.Name)
+			steps--
+		}
+		return nil
+	})
+}
+
+// Baseline records every migration up to version as applied without running
+// it, for databases created before migrations existed.
+func (m *Migrator) Baseline(ctx context.Context, version int) error {
+	return m.locked(ctx, func(conn *pgx.Conn, applied map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok || migration.Version > version {
+				continue
+			}
+
+			if _, err := conn.ExecEx(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", nil,
+				migration.Version, migration.Name); err != nil {
+				return err
+			}
+			m.log.Info("migration baselined", "version", migration.Version, "name", migration.Name)
+		}
+		return nil
+	})
+}
+
+// Status lists every known migration and when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.locked(ctx, func(conn *pgx.Conn, applied map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			status := Status{Migration: migration}
+			if at, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &at
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// locked runs fn on a dedicated connection holding the advisory lock, with
+// the versions applied so far.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgx.Conn, applied map[int]time.Time) error) error {
+	conn, err := m.pool.AcquireEx(ctx)
+	if err != nil {
+		return err
+	}
+	defer m.pool.Release(conn)
+
+	if _, err = conn.ExecEx(ctx, "SELECT pg_advisory_lock($1)", nil, int64(lockID)); err != nil {
+		return fmt.Errorf("migrations: taking the lock: %v", err)
+	}
+	defer func() {
+		if _, err := conn.ExecEx(context.Background(), "SELECT pg_advisory_unlock($1)", nil, int64(lockID)); err != nil {
+			m.log.Error("releasing the migration lock", "error", err)
+		}
+	}()
+
+	_, err = conn.ExecEx(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations
+(
+    version    INTEGER PRIMARY KEY,
+    name       TEXT                                   NOT NULL,
+    applied_at TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL
+)`, nil)
+	if err != nil {
+		return fmt.Errorf("migrations: creating schema_migrations: %v", err)
+	}
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	return fn(conn, applied)
+}
+
+func appliedVersions(ctx context.Context, conn *pgx.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryEx(ctx, "SELECT version, applied_at FROM schema_migrations", nil)
+	if err != nil {
+		return nil, fmt.Errorf("migrations: reading schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err = rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+
+	return applied, rows.Err()
+}
+
+// apply runs script and the bookkeeping statement in one transaction.
+func (m *Migrator) apply(ctx context.Context, conn *pgx.Conn, script string, record string, args ...interface{}) error {
+	tx, err := conn.BeginEx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.ExecEx(ctx, script, nil); err != nil {
+		return err
+	}
+	if _, err = tx.ExecEx(ctx, record, nil, args...); err != nil {
+		return err
+	}
+
+	return tx.CommitEx(ctx)
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS posts, votes, forum_users, threads, forums, users CASCADE;
+DROP FUNCTION IF EXISTS update_path();
+DROP FUNCTION IF EXISTS update_user_forum();
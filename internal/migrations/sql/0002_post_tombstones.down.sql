@@ -0,0 +1,3 @@
+ALTER TABLE posts
+    DROP COLUMN deleted_at,
+    DROP COLUMN deleted;
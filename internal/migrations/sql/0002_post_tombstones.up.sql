@@ -0,0 +1,3 @@
+ALTER TABLE posts
+    ADD COLUMN deleted    BOOLEAN DEFAULT false NOT NULL,
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,7 @@
+DROP INDEX idx_thread_archived;
+DROP INDEX idx_thread_coverage;
+CREATE INDEX idx_thread_coverage ON threads (forum, created, id, slug, author, title, message, votes);
+
+ALTER TABLE threads
+    DROP CONSTRAINT threads_state_check,
+    DROP COLUMN state;
@@ -0,0 +1,10 @@
+ALTER TABLE threads
+    ADD COLUMN state TEXT DEFAULT 'open' NOT NULL,
+    ADD CONSTRAINT threads_state_check CHECK (state IN ('open', 'locked', 'archived', 'deleted'));
+
+-- Forum listings only read open and locked threads; archived threads get an
+-- index of their own and deleted ones none at all.
+DROP INDEX idx_thread_coverage;
+CREATE INDEX idx_thread_coverage ON threads (forum, created, id, slug, author, title, message, votes, state)
+    WHERE state IN ('open', 'locked');
+CREATE INDEX idx_thread_archived ON threads (forum, created) WHERE state = 'archived';
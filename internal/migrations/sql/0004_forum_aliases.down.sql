@@ -0,0 +1,11 @@
+DROP TABLE forum_aliases;
+
+ALTER TABLE forum_users
+    DROP CONSTRAINT forum_users_forum_fkey,
+    ADD CONSTRAINT forum_users_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug);
+ALTER TABLE posts
+    DROP CONSTRAINT posts_forum_fkey,
+    ADD CONSTRAINT posts_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug);
+ALTER TABLE threads
+    DROP CONSTRAINT threads_forum_fkey,
+    ADD CONSTRAINT threads_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug);
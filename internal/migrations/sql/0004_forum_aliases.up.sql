@@ -0,0 +1,19 @@
+-- Renaming a forum rewrites its slug everywhere it is referenced.
+ALTER TABLE threads
+    DROP CONSTRAINT threads_forum_fkey,
+    ADD CONSTRAINT threads_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug) ON UPDATE CASCADE;
+ALTER TABLE posts
+    DROP CONSTRAINT posts_forum_fkey,
+    ADD CONSTRAINT posts_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug) ON UPDATE CASCADE;
+ALTER TABLE forum_users
+    DROP CONSTRAINT forum_users_forum_fkey,
+    ADD CONSTRAINT forum_users_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug) ON UPDATE CASCADE;
+
+-- Old slugs of renamed forums, still accepted wherever a forum is looked up.
+CREATE UNLOGGED TABLE forum_aliases
+(
+    slug    CITEXT                                 NOT NULL PRIMARY KEY,
+    forum   CITEXT                                 NOT NULL REFERENCES forums (slug) ON UPDATE CASCADE ON DELETE CASCADE,
+    created TIMESTAMP WITH TIME ZONE DEFAULT now() NOT NULL
+);
+CREATE INDEX idx_forum_aliases_forum ON forum_aliases (forum);
@@ -0,0 +1,17 @@
+DROP TABLE user_aliases;
+
+ALTER TABLE forum_users
+    DROP CONSTRAINT forum_users_nickname_fkey,
+    ADD CONSTRAINT forum_users_nickname_fkey FOREIGN KEY (nickname) REFERENCES users (nickname);
+ALTER TABLE votes
+    DROP CONSTRAINT votes_user_nick_fkey,
+    ADD CONSTRAINT votes_user_nick_fkey FOREIGN KEY (user_nick) REFERENCES users (nickname);
+ALTER TABLE posts
+    DROP CONSTRAINT posts_author_fkey,
+    ADD CONSTRAINT posts_author_fkey FOREIGN KEY (author) REFERENCES users (nickname);
+ALTER TABLE threads
+    DROP CONSTRAINT threads_author_fkey,
+    ADD CONSTRAINT threads_author_fkey FOREIGN KEY (author) REFERENCES users (nickname);
+ALTER TABLE forums
+    DROP CONSTRAINT forums_user_nick_fkey,
+    ADD CONSTRAINT forums_user_nick_fkey FOREIGN KEY (user_nick) REFERENCES users (nickname);
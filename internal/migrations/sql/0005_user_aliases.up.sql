@@ -0,0 +1,26 @@
+-- Renaming a user rewrites the nickname everywhere it is referenced.
+ALTER TABLE forums
+    DROP CONSTRAINT forums_user_nick_fkey,
+    ADD CONSTRAINT forums_user_nick_fkey FOREIGN KEY (user_nick) REFERENCES users (nickname) ON UPDATE CASCADE;
+ALTER TABLE threads
+    DROP CONSTRAINT threads_author_fkey,
+    ADD CONSTRAINT threads_author_fkey FOREIGN KEY (author) REFERENCES users (nickname) ON UPDATE CASCADE;
+ALTER TABLE posts
+    DROP CONSTRAINT posts_author_fkey,
+    ADD CONSTRAINT posts_author_fkey FOREIGN KEY (author) REFERENCES users (nickname) ON UPDATE CASCADE;
+ALTER TABLE votes
+    DROP CONSTRAINT votes_user_nick_fkey,
+    ADD CONSTRAINT votes_user_nick_fkey FOREIGN KEY (user_nick) REFERENCES users (nickname) ON UPDATE CASCADE;
+ALTER TABLE forum_users
+    DROP CONSTRAINT forum_users_nickname_fkey,
+    ADD CONSTRAINT forum_users_nickname_fkey FOREIGN KEY (nickname) REFERENCES users (nickname) ON UPDATE CASCADE;
+
+-- Old nicknames of renamed users. Until reserved_until nobody else can take
+-- them and profile lookups redirect to the new nickname.
+CREATE UNLOGGED TABLE user_aliases
+(
+    nickname       CITEXT                   NOT NULL PRIMARY KEY,
+    user_nick      CITEXT                   NOT NULL REFERENCES users (nickname) ON UPDATE CASCADE ON DELETE CASCADE,
+    reserved_until TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX idx_user_aliases_user ON user_aliases (user_nick);
@@ -0,0 +1,2 @@
+DROP INDEX post_author_index;
+DROP INDEX idx_thread_author;
@@ -0,0 +1,3 @@
+-- Looking up everything a user wrote, e.g. to export or delete it.
+CREATE INDEX idx_thread_author ON threads (author);
+CREATE INDEX post_author_index ON posts (author);
@@ -0,0 +1,11 @@
+-- Every message a post had before an edit, with the user who replaced it.
+-- Revisions are numbered from 1 per post, oldest first.
+CREATE UNLOGGED TABLE post_revisions
+(
+    post     INTEGER                  NOT NULL REFERENCES posts (ID) ON DELETE CASCADE,
+    revision INTEGER                  NOT NULL,
+    message  TEXT                     NOT NULL,
+    editor   CITEXT                   NOT NULL REFERENCES users (nickname) ON UPDATE CASCADE,
+    edited   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (post, revision)
+);
@@ -0,0 +1,4 @@
+DROP TABLE thread_revisions;
+
+ALTER TABLE threads
+    DROP COLUMN edited_at;
@@ -0,0 +1,17 @@
+ALTER TABLE threads
+    ADD COLUMN edited_at TIMESTAMP WITH TIME ZONE;
+
+-- Every change of the title or message of a thread. A field that the change
+-- left alone is NULL in both its old_ and new_ columns.
+CREATE UNLOGGED TABLE thread_revisions
+(
+    thread      INTEGER                  NOT NULL REFERENCES threads (ID) ON DELETE CASCADE,
+    revision    INTEGER                  NOT NULL,
+    old_title   TEXT,
+    new_title   TEXT,
+    old_message TEXT,
+    new_message TEXT,
+    editor      CITEXT                   NOT NULL REFERENCES users (nickname) ON UPDATE CASCADE,
+    edited      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (thread, revision)
+);
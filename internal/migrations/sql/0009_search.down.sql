@@ -0,0 +1,9 @@
+DROP TRIGGER threads_search ON threads;
+DROP TRIGGER posts_search ON posts;
+DROP FUNCTION threads_search();
+DROP FUNCTION posts_search();
+
+ALTER TABLE threads
+    DROP COLUMN search;
+ALTER TABLE posts
+    DROP COLUMN search;
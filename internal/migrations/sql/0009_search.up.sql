@@ -0,0 +1,42 @@
+-- Full-text search. The 'simple' configuration does no stemming, as posts
+-- come in many languages. Triggers keep the vectors in step with the text.
+ALTER TABLE posts
+    ADD COLUMN search TSVECTOR;
+ALTER TABLE threads
+    ADD COLUMN search TSVECTOR;
+
+CREATE OR REPLACE FUNCTION posts_search() RETURNS TRIGGER AS
+$posts_search$
+BEGIN
+    NEW.search := to_tsvector('simple', NEW.message);
+    RETURN NEW;
+END
+$posts_search$ LANGUAGE plpgsql;
+
+-- Titles weigh more than messages.
+CREATE OR REPLACE FUNCTION threads_search() RETURNS TRIGGER AS
+$threads_search$
+BEGIN
+    NEW.search := setweight(to_tsvector('simple', NEW.title), 'A') ||
+                  setweight(to_tsvector('simple', NEW.message), 'B');
+    RETURN NEW;
+END
+$threads_search$ LANGUAGE plpgsql;
+
+CREATE TRIGGER posts_search
+    BEFORE INSERT OR UPDATE OF message
+    ON posts
+    FOR EACH ROW
+EXECUTE PROCEDURE posts_search();
+
+CREATE TRIGGER threads_search
+    BEFORE INSERT OR UPDATE OF title, message
+    ON threads
+    FOR EACH ROW
+EXECUTE PROCEDURE threads_search();
+
+UPDATE posts SET search = to_tsvector('simple', message);
+UPDATE threads SET search = setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', message), 'B');
+
+CREATE INDEX idx_post_search ON posts USING gin (search);
+CREATE INDEX idx_thread_search ON threads USING gin (search);
@@ -0,0 +1,4 @@
+DROP INDEX post_author_index;
+DROP INDEX idx_thread_author;
+CREATE INDEX idx_thread_author ON threads (author);
+CREATE INDEX post_author_index ON posts (author);
@@ -0,0 +1,5 @@
+-- Listings of what a user wrote go by date for threads and by ID for posts.
+DROP INDEX idx_thread_author;
+DROP INDEX post_author_index;
+CREATE INDEX idx_thread_author ON threads (author, created);
+CREATE INDEX post_author_index ON posts (author, id);
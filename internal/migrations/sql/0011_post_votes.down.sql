@@ -0,0 +1,6 @@
+DROP INDEX idx_post_top;
+DROP TABLE post_reactions;
+DROP TABLE post_votes;
+
+ALTER TABLE posts
+    DROP COLUMN score;
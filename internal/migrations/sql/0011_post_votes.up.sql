@@ -0,0 +1,25 @@
+-- Votes on posts keep a running score on the post, like votes on threads do
+-- in threads.votes.
+ALTER TABLE posts
+    ADD COLUMN score INTEGER DEFAULT 0 NOT NULL;
+
+CREATE UNLOGGED TABLE post_votes
+(
+    post      INTEGER NOT NULL REFERENCES posts (ID) ON DELETE CASCADE,
+    user_nick CITEXT  NOT NULL REFERENCES users (nickname) ON UPDATE CASCADE,
+    voice     BOOLEAN NOT NULL,
+    PRIMARY KEY (post, user_nick)
+);
+CREATE INDEX idx_post_votes_user ON post_votes (user_nick);
+
+-- A user gives each reaction to a post at most once.
+CREATE UNLOGGED TABLE post_reactions
+(
+    post      INTEGER NOT NULL REFERENCES posts (ID) ON DELETE CASCADE,
+    user_nick CITEXT  NOT NULL REFERENCES users (nickname) ON UPDATE CASCADE,
+    reaction  TEXT    NOT NULL,
+    PRIMARY KEY (post, user_nick, reaction)
+);
+CREATE INDEX idx_post_reactions_user ON post_reactions (user_nick);
+
+CREATE INDEX idx_post_top ON posts (thread, score DESC, id);
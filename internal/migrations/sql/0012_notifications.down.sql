@@ -0,0 +1,3 @@
+DROP TABLE notifications;
+DROP TABLE forum_subscriptions;
+DROP TABLE thread_subscriptions;
@@ -0,0 +1,37 @@
+CREATE UNLOGGED TABLE thread_subscriptions
+(
+    user_nick CITEXT                   NOT NULL REFERENCES users (nickname) ON UPDATE CASCADE,
+    thread    INTEGER                  NOT NULL REFERENCES threads (ID) ON DELETE CASCADE,
+    created   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (thread, user_nick)
+);
+CREATE INDEX idx_thread_subscriptions_user ON thread_subscriptions (user_nick);
+
+CREATE UNLOGGED TABLE forum_subscriptions
+(
+    user_nick CITEXT                   NOT NULL REFERENCES users (nickname) ON UPDATE CASCADE,
+    forum     CITEXT                   NOT NULL REFERENCES forums (slug) ON UPDATE CASCADE ON DELETE CASCADE,
+    created   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (forum, user_nick)
+);
+CREATE INDEX idx_forum_subscriptions_user ON forum_subscriptions (user_nick);
+
+-- kind is one of
+--   post:   a new post in a subscribed thread,
+--   reply:  a reply to a post of the user,
+--   thread: a new thread in a subscribed forum,
+--   vote:   a vote for a thread of the user.
+CREATE UNLOGGED TABLE notifications
+(
+    ID        SERIAL                   NOT NULL PRIMARY KEY,
+    user_nick CITEXT                   NOT NULL REFERENCES users (nickname) ON UPDATE CASCADE,
+    kind      TEXT                     NOT NULL,
+    thread    INTEGER                  NOT NULL REFERENCES threads (ID) ON DELETE CASCADE,
+    post      INTEGER REFERENCES posts (ID) ON DELETE CASCADE,
+    actor     CITEXT                   NOT NULL REFERENCES users (nickname) ON UPDATE CASCADE,
+    created   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    read      BOOLEAN                  NOT NULL DEFAULT false
+);
+CREATE INDEX idx_notifications_user ON notifications (user_nick, ID);
+CREATE INDEX idx_notifications_unread ON notifications (user_nick) WHERE NOT read;
+CREATE INDEX idx_notifications_actor ON notifications (actor);
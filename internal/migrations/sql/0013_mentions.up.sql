@@ -0,0 +1,14 @@
+-- A mention of a user in the message of a thread (post is NULL) or of a post.
+CREATE UNLOGGED TABLE mentions
+(
+    ID        SERIAL                   NOT NULL PRIMARY KEY,
+    user_nick CITEXT                   NOT NULL REFERENCES users (nickname) ON UPDATE CASCADE,
+    thread    INTEGER                  NOT NULL REFERENCES threads (ID) ON DELETE CASCADE,
+    post      INTEGER REFERENCES posts (ID) ON DELETE CASCADE,
+    author    CITEXT                   NOT NULL REFERENCES users (nickname) ON UPDATE CASCADE,
+    created   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_mentions_user ON mentions (user_nick, ID);
+CREATE INDEX idx_mentions_thread ON mentions (thread) WHERE post IS NULL;
+CREATE INDEX idx_mentions_post ON mentions (post);
+CREATE INDEX idx_mentions_author ON mentions (author);
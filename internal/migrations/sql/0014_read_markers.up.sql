@@ -0,0 +1,10 @@
+-- post is the highest post ID of the thread the user has read.
+CREATE UNLOGGED TABLE read_markers
+(
+    user_nick CITEXT                   NOT NULL REFERENCES users (nickname) ON UPDATE CASCADE,
+    thread    INTEGER                  NOT NULL REFERENCES threads (ID) ON DELETE CASCADE,
+    post      INTEGER                  NOT NULL,
+    updated   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_nick, thread)
+);
+CREATE INDEX idx_read_markers_thread ON read_markers (thread);
@@ -0,0 +1,6 @@
+DROP TRIGGER thread_tags_count ON threads;
+DROP FUNCTION thread_tags_count();
+DROP TABLE tags;
+
+ALTER TABLE threads
+    DROP COLUMN tags;
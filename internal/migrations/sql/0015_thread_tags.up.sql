@@ -0,0 +1,35 @@
+-- Tags are stored lower-cased. The tags table is the dictionary of every tag
+-- with the number of threads, deleted ones aside, that carry it; a trigger
+-- keeps the numbers in step with the threads.
+ALTER TABLE threads
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE UNLOGGED TABLE tags
+(
+    name    TEXT    NOT NULL PRIMARY KEY,
+    threads INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX idx_tags_threads ON tags (threads DESC, name);
+
+CREATE OR REPLACE FUNCTION thread_tags_count() RETURNS TRIGGER AS
+$thread_tags_count$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.state <> 'deleted' THEN
+        UPDATE tags SET threads = threads - 1 WHERE name = ANY (OLD.tags);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.state <> 'deleted' THEN
+        INSERT INTO tags (name, threads)
+        SELECT unnest(NEW.tags), 1
+        ON CONFLICT (name) DO UPDATE SET threads = tags.threads + 1;
+    END IF;
+    RETURN NULL;
+END
+$thread_tags_count$ LANGUAGE plpgsql;
+
+CREATE TRIGGER thread_tags_count
+    AFTER INSERT OR DELETE OR UPDATE OF tags, state
+    ON threads
+    FOR EACH ROW
+EXECUTE PROCEDURE thread_tags_count();
+
+CREATE INDEX idx_thread_tags ON threads USING gin (tags);
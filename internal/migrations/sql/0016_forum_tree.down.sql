@@ -0,0 +1,2 @@
+ALTER TABLE forums
+    DROP COLUMN parent;
@@ -0,0 +1,5 @@
+-- Forums form a tree: a forum with no parent is a category or a top-level
+-- forum. A forum with subforums cannot be deleted.
+ALTER TABLE forums
+    ADD COLUMN parent CITEXT REFERENCES forums (slug) ON UPDATE CASCADE;
+CREATE INDEX idx_forums_parent ON forums (parent, slug);
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+//easyjson:json
+type SlowQuery struct {
+	Name       string    `json:"name,omitempty"`
+	Method     string    `json:"method"`
+	SQL        string    `json:"sql"`
+	Args       []string  `json:"args"`
+	DurationMs float64   `json:"durationMs"`
+	CapturedAt time.Time `json:"capturedAt"`
+	Plan       string    `json:"plan,omitempty"`
+}
+
+//easyjson:json
+type SlowQueries []SlowQuery
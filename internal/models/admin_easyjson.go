@@ -0,0 +1,233 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson9280440fDecodeGithubComEgorAistTPDBProjectInternalModels(in *jlexer.Lexer, out *SlowQuery) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "name":
+			out.Name = string(in.String())
+		case "method":
+			out.Method = string(in.String())
+		case "sql":
+			out.SQL = string(in.String())
+		case "args":
+			if in.IsNull() {
+				in.Skip()
+				out.Args = nil
+			} else {
+				in.Delim('[')
+				if out.Args == nil {
+					if !in.IsDelim(']') {
+						out.Args = make([]string, 0, 4)
+					} else {
+						out.Args = []string{}
+					}
+				} else {
+					out.Args = (out.Args)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					v1 = string(in.String())
+					out.Args = append(out.Args, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "durationMs":
+			out.DurationMs = float64(in.Float64())
+		case "capturedAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CapturedAt).UnmarshalJSON(data))
+			}
+		case "plan":
+			out.Plan = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9280440fEncodeGithubComEgorAistTPDBProjectInternalModels(out *jwriter.Writer, in SlowQuery) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Name != "" {
+		const prefix string = ",\"name\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"method\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Method))
+	}
+	{
+		const prefix string = ",\"sql\":"
+		out.RawString(prefix)
+		out.String(string(in.SQL))
+	}
+	{
+		const prefix string = ",\"args\":"
+		out.RawString(prefix)
+		if in.Args == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Args {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v3))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"durationMs\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.DurationMs))
+	}
+	{
+		const prefix string = ",\"capturedAt\":"
+		out.RawString(prefix)
+		out.Raw((in.CapturedAt).MarshalJSON())
+	}
+	if in.Plan != "" {
+		const prefix string = ",\"plan\":"
+		out.RawString(prefix)
+		out.String(string(in.Plan))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SlowQuery) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9280440fEncodeGithubComEgorAistTPDBProjectInternalModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SlowQuery) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9280440fEncodeGithubComEgorAistTPDBProjectInternalModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SlowQuery) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9280440fDecodeGithubComEgorAistTPDBProjectInternalModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SlowQuery) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9280440fDecodeGithubComEgorAistTPDBProjectInternalModels(l, v)
+}
+func easyjson9280440fDecodeGithubComEgorAistTPDBProjectInternalModels1(in *jlexer.Lexer, out *SlowQueries) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(SlowQueries, 0, 1)
+			} else {
+				*out = SlowQueries{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v4 SlowQuery
+			(v4).UnmarshalEasyJSON(in)
+			*out = append(*out, v4)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9280440fEncodeGithubComEgorAistTPDBProjectInternalModels1(out *jwriter.Writer, in SlowQueries) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v5, v6 := range in {
+			if v5 > 0 {
+				out.RawByte(',')
+			}
+			(v6).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SlowQueries) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9280440fEncodeGithubComEgorAistTPDBProjectInternalModels1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SlowQueries) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9280440fEncodeGithubComEgorAistTPDBProjectInternalModels1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SlowQueries) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9280440fDecodeGithubComEgorAistTPDBProjectInternalModels1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SlowQueries) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9280440fDecodeGithubComEgorAistTPDBProjectInternalModels1(l, v)
+}
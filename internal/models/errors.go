@@ -0,0 +1,117 @@
+package models
+
+// ErrorKind classifies an Error; the handlers map each kind to one HTTP status.
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindUnavailable
+	KindTimeout
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not found"
+	case KindConflict:
+		return "conflict"
+	case KindValidation:
+		return "validation failed"
+	case KindUnavailable:
+		return "unavailable"
+	case KindTimeout:
+		return "timeout"
+	default:
+		return "internal error"
+	}
+}
+
+// Entities an Error can refer to.
+const (
+	EntityUser   = "user"
+	EntityForum  = "forum"
+	EntityThread = "thread"
+	EntityPost   = "post"
+	EntityVote   = "vote"
+)
+
+// Machine-readable reasons returned to clients alongside the message.
+const (
+	ReasonNotFound       = "not_found"
+	ReasonAlreadyExists  = "already_exists"
+	ReasonParentConflict = "parent_conflict"
+	ReasonDeleted        = "deleted"
+	ReasonLocked         = "locked"
+	ReasonArchived       = "archived"
+	ReasonHasChildren    = "has_children"
+	ReasonReserved       = "reserved"
+	ReasonInvalidInput   = "invalid_input"
+	ReasonMalformedBody  = "malformed_body"
+	ReasonTimeout        = "timeout"
+	ReasonCancelled      = "cancelled"
+	ReasonNoConnection   = "no_connection"
+	ReasonInternal       = "internal"
+)
+
+// Sentinels for errors.Is. A sentinel matches every Error of its kind;
+// compare against an Error with Entity or Reason set to narrow the match.
+var (
+	ErrNotFound    = Error{Kind: KindNotFound}
+	ErrConflict    = Error{Kind: KindConflict}
+	ErrValidation  = Error{Kind: KindValidation}
+	ErrInternal    = Error{Kind: KindInternal}
+	ErrUnavailable = Error{Kind: KindUnavailable}
+	ErrTimeout     = Error{Kind: KindTimeout}
+)
+
+// Error is the error returned by storages and services. Fields lists the
+// offending fields of a Validation error.
+type Error struct {
+	Kind    ErrorKind
+	Entity  string
+	Reason  string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func NewNotFound(entity string) Error {
+	return Error{Kind: KindNotFound, Entity: entity, Reason: ReasonNotFound, Message: "can't find " + entity}
+}
+
+func NewConflict(entity string, reason string, message string) Error {
+	return Error{Kind: KindConflict, Entity: entity, Reason: reason, Message: message}
+}
+
+func NewInternal(err error) Error {
+	return Error{Kind: KindInternal, Reason: ReasonInternal, Message: "internal error", Err: err}
+}
+
+func (e Error) Error() string {
+	message := e.Message
+	if message == "" {
+		message = e.Kind.String()
+	}
+	if e.Err != nil {
+		return message + ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+func (e Error) Is(target error) bool {
+	t, ok := target.(Error)
+	if !ok {
+		return false
+	}
+
+	return t.Kind == e.Kind &&
+		(t.Entity == "" || t.Entity == e.Entity) &&
+		(t.Reason == "" || t.Reason == e.Reason)
+}
@@ -7,16 +7,9 @@ import (
 //easyjson:json
 type RespError struct {
 	Message string `json:"message"`
-}
-
-//easyjson:json
-type Error struct {
-	Code string `json:"-"`
-	Message string `json:"message"`
-}
-
-func (e Error) Error() string {
-	return e.Code
+	Entity string `json:"entity,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	Fields []FieldError `json:"fields,omitempty"`
 }
 
 //easyjson:json
@@ -26,6 +19,15 @@ type Forum struct {
 	User string `json:"user,omitempty"`
 	Threads int `json:"threads,omitempty"`
 	Posts int `json:"posts,omitempty"`
+	Parent string `json:"parent,omitempty"` // Родительский форум; нет у разделов и форумов верхнего уровня.
+	Totals *ForumTotals `json:"totals,omitempty"` // Счётчики вместе с подфорумами; только в ForumGet.
+}
+
+//easyjson:json
+type ForumTotals struct {
+	Threads int `json:"threads"`
+	Posts int `json:"posts"`
+	Subforums int `json:"subforums"` // Число всех потомков, не только прямых.
 }
 
 //easyjson:json
@@ -33,6 +35,28 @@ type ForumCreate struct {
 	Slug string `json:"slug"`
 	Title string `json:"title"`
 	User string `json:"user"`
+	Parent string `json:"parent,omitempty"`
+}
+
+//easyjson:json
+type ForumUpdate struct {
+	Slug string `json:"-"`
+	NewSlug string `json:"slug"`
+	Title string `json:"title"`
+	User string `json:"user"`
+	Parent *string `json:"parent"` // Без поля родитель не меняется, "" делает форум верхнего уровня.
+}
+
+//easyjson:json
+type ForumDeletion struct {
+	Forum string `json:"forum"`
+	Threads int `json:"threads"`
+	Posts int `json:"posts"`
+	Votes int `json:"votes"`
+	PostVotes int `json:"postVotes"`
+	Users int `json:"users"`
+	Aliases int `json:"aliases"`
+	DryRun bool `json:"dryRun"`
 }
 
 type ForumInput struct {
@@ -51,6 +75,10 @@ type ForumGetThreads struct {
 	Limit int
 	Since string
 	Desc bool
+	Archived bool
+	Viewer string
+	Tags []string
+	Descendants bool // Вместе с ветками всех подфорумов.
 }
 
 type UserInput struct {
@@ -63,6 +91,96 @@ type User struct {
 	Fullname string `json:"fullname,omitempty"`
 	Email string `json:"email,omitempty"`
 	About string `json:"about,omitempty"`
+	Counters *UserCounters `json:"counters,omitempty"` // Только в профиле.
+}
+
+//easyjson:json
+type UserCounters struct {
+	Posts int `json:"posts"`
+	Threads int `json:"threads"`
+	Votes int `json:"votes"` // Голоса за ветки и за сообщения вместе.
+}
+
+type UserGetThreads struct {
+	Nickname string
+	Forum string
+	Limit int
+	Since string
+	Desc bool
+}
+
+type UserGetPosts struct {
+	Nickname string
+	Forum string
+	Limit int
+	Since int
+	Desc bool
+}
+
+//easyjson:json
+type UserRename struct {
+	Nickname string `json:"-"`
+	NewNickname string `json:"nickname"`
+}
+
+// User deletion modes. Anonymize keeps the content under a pseudonym, purge
+// removes it as well.
+const (
+	DeleteAnonymize = "anonymize"
+	DeletePurge     = "purge"
+)
+
+//easyjson:json
+type UserDeletion struct {
+	Nickname string `json:"nickname"`
+	Mode string `json:"mode"`
+	Threads int `json:"threads"`
+	Posts int `json:"posts"`
+	Votes int `json:"votes"`
+}
+
+//easyjson:json
+type UserVote struct {
+	Thread int `json:"thread"`
+	Voice int `json:"voice"`
+}
+
+//easyjson:json
+type UserPostVote struct {
+	Post int `json:"post"`
+	Voice int `json:"voice"`
+}
+
+//easyjson:json
+type UserPostReaction struct {
+	Post int `json:"post"`
+	Reaction string `json:"reaction"`
+}
+
+//easyjson:json
+type UserExport struct {
+	User User `json:"user"`
+	Aliases []string `json:"aliases"`
+	Forums []Forum `json:"forums"`
+	Memberships []string `json:"memberships"`
+	Threads []Thread `json:"threads"`
+	Posts []Post `json:"posts"`
+	Votes []UserVote `json:"votes"`
+	PostVotes []UserPostVote `json:"postVotes"`
+	Reactions []UserPostReaction `json:"reactions"`
+	PostRevisions []UserPostRevision `json:"postRevisions"`
+	ThreadRevisions []UserThreadRevision `json:"threadRevisions"`
+	Subscriptions []Subscription `json:"subscriptions"`
+	Notifications []Notification `json:"notifications"`
+	Mentions []Mention `json:"mentions"`
+	ReadMarkers []UserReadMarker `json:"readMarkers"`
+}
+
+// ForumCounters is a change of the counters of a forum.
+type ForumCounters struct {
+	Forum string
+	Threads int
+	Posts int
 }
 
 //easyjson:json
@@ -75,6 +193,27 @@ type Thread struct {
 	Slug    string    `json:"slug,omitempty"`
 	Title   string    `json:"title,omitempty"`
 	Votes   int       `json:"votes,omitempty"`
+	State   string    `json:"state,omitempty"`
+	IsEdited bool       `json:"edited,omitempty"`
+	EditedAt *time.Time `json:"editedAt,omitempty"`
+	UnknownMentions []string `json:"unknownMentions,omitempty"` // Упомянутые ники, которых нет среди пользователей.
+	Unread *int `json:"unread,omitempty"` // Число непрочитанных сообщений; только если задан viewer.
+	Tags []string `json:"tags,omitempty"` // Метки ветки, в нижнем регистре.
+}
+
+// Thread states. Locked threads take no new posts or votes, archived threads
+// are read-only and deleted threads are gone for good.
+const (
+	ThreadOpen     = "open"
+	ThreadLocked   = "locked"
+	ThreadArchived = "archived"
+	ThreadDeleted  = "deleted"
+)
+
+//easyjson:json
+type ThreadState struct {
+	ThreadInput
+	State string `json:"state"`
 }
 
 //easyjson:json
@@ -88,6 +227,38 @@ type ThreadUpdate struct {
 	ThreadInput
 	Title    string `json:"title"`
 	Message  string `json:"message"`
+	Editor   string `json:"editor,omitempty"` // Кто правит ветку; по умолчанию автор.
+	Tags     []string `json:"tags"`          // Новые метки; без поля метки не меняются, [] снимает все.
+}
+
+type ThreadGetHistory struct {
+	ThreadInput
+	Limit int
+	Desc  bool
+}
+
+//easyjson:json
+type FieldChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+//easyjson:json
+type ThreadRevision struct {
+	Revision int          `json:"revision"`
+	Title    *FieldChange `json:"title,omitempty"`   // Нет, если заголовок не менялся.
+	Message  *FieldChange `json:"message,omitempty"` // Нет, если сообщение не менялось.
+	Editor   string       `json:"editor"`
+	Edited   time.Time    `json:"edited"`
+}
+
+//easyjson:json
+type UserThreadRevision struct {
+	Thread   int          `json:"thread"`
+	Revision int          `json:"revision"`
+	Title    *FieldChange `json:"title,omitempty"`
+	Message  *FieldChange `json:"message,omitempty"`
+	Edited   time.Time    `json:"edited"`
 }
 
 type ThreadGetPosts struct {
@@ -106,6 +277,7 @@ type PostInput struct {
 type PostUpdate struct {
 	ID       int  `json:"id"`
 	Message string `json:"message"`
+	Editor  string `json:"editor,omitempty"` // Кто правит сообщение; по умолчанию автор.
 }
 //easyjson:json
 type PostCreate struct {
@@ -123,6 +295,33 @@ type Post struct {
 	IsEdited bool   `json:"isEdited,omitempty"` // Истина, если данное сообщение было изменено.
 	Forum    string `json:"forum,omitempty"`    // Идентификатор форума (slug) данного сообещния.
 	Created  string `json:"created,omitempty"`
+	IsDeleted bool  `json:"isDeleted,omitempty"` // Истина, если сообщение удалено; текст заменён на DeletedPostMessage.
+	Score    int    `json:"score,omitempty"`    // Сумма голосов за сообщение.
+	Reactions map[string]int `json:"reactions,omitempty"` // Число реакций каждого вида; только в ответах на голос и реакцию.
+	UnknownMentions []string `json:"unknownMentions,omitempty"` // Упомянутые ники, которых нет среди пользователей.
+}
+
+//easyjson:json
+type PostVote struct {
+	User  string `json:"nickname"`
+	Voice int    `json:"voice"`
+	Post  int    `json:"-"`
+}
+
+//easyjson:json
+type PostReaction struct {
+	User     string `json:"nickname"`
+	Reaction string `json:"reaction"`
+	Post     int    `json:"-"`
+}
+
+// DeletedPostMessage replaces the message of a deleted post, which keeps its
+// place in the tree so that its replies still render under it.
+const DeletedPostMessage = "[deleted]"
+
+//easyjson:json
+type PostsPurged struct {
+	Posts int `json:"posts"`
 }
 
 //easyjson:json
@@ -131,6 +330,47 @@ type PostFull struct {
 	Forum *Forum `json:"forum,omitempty"`
 	Post *Post `json:"post,omitempty"`
 	Thread *Thread `json:"thread,omitempty"`
+	History []PostRevision `json:"history,omitempty"`
+}
+
+// PostFullHistory is how many of the latest revisions related=history embeds.
+const PostFullHistory = 5
+
+// Operations of a DiffLine.
+const (
+	DiffEqual  = "="
+	DiffInsert = "+"
+	DiffDelete = "-"
+)
+
+//easyjson:json
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+//easyjson:json
+type PostRevision struct {
+	Revision int `json:"revision"`
+	Message string `json:"message"`    // Текст сообщения до правки.
+	Editor string `json:"editor"`      // Кто заменил этот текст.
+	Edited time.Time `json:"edited"`   // Когда он был заменён.
+	Diff []DiffLine `json:"diff"`      // Построчные изменения до следующей версии.
+	Next string `json:"-"`             // Следующая версия: текст следующей правки или текущий.
+}
+
+//easyjson:json
+type UserPostRevision struct {
+	Post int `json:"post"`
+	Revision int `json:"revision"`
+	Message string `json:"message"`    // Текст сообщения до правки.
+	Edited time.Time `json:"edited"`
+}
+
+type PostGetHistory struct {
+	ID    int
+	Limit int
+	Desc  bool
 }
 
 //easyjson:json
@@ -7,6 +7,7 @@ import (
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
 )
 
 // suppress unused package warning
@@ -17,7 +18,7 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels(in *jlexer.Lexer, out *Vote) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels(in *jlexer.Lexer, out *Vote) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -52,7 +53,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels(in *j
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels(out *jwriter.Writer, in Vote) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels(out *jwriter.Writer, in Vote) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -77,27 +78,27 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels(out *
 // MarshalJSON supports json.Marshaler interface
 func (v Vote) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Vote) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels(w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Vote) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Vote) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels(l, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels1(in *jlexer.Lexer, out *UserInput) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels1(in *jlexer.Lexer, out *UserVote) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -116,8 +117,10 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels1(in *
 			continue
 		}
 		switch key {
-		case "Nickname":
-			out.Nickname = string(in.String())
+		case "thread":
+			out.Thread = int(in.Int())
+		case "voice":
+			out.Voice = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -128,42 +131,47 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels1(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels1(out *jwriter.Writer, in UserInput) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels1(out *jwriter.Writer, in UserVote) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Nickname\":"
+		const prefix string = ",\"thread\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Nickname))
+		out.Int(int(in.Thread))
+	}
+	{
+		const prefix string = ",\"voice\":"
+		out.RawString(prefix)
+		out.Int(int(in.Voice))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v UserInput) MarshalJSON() ([]byte, error) {
+func (v UserVote) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels1(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v UserInput) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels1(w, v)
+func (v UserVote) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *UserInput) UnmarshalJSON(data []byte) error {
+func (v *UserVote) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels1(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *UserInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels1(l, v)
+func (v *UserVote) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels1(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels2(in *jlexer.Lexer, out *User) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels2(in *jlexer.Lexer, out *UserThreadRevision) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -182,14 +190,34 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels2(in *
 			continue
 		}
 		switch key {
-		case "nickname":
-			out.Nickname = string(in.String())
-		case "fullname":
-			out.Fullname = string(in.String())
-		case "email":
-			out.Email = string(in.String())
-		case "about":
-			out.About = string(in.String())
+		case "thread":
+			out.Thread = int(in.Int())
+		case "revision":
+			out.Revision = int(in.Int())
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+				out.Title = nil
+			} else {
+				if out.Title == nil {
+					out.Title = new(FieldChange)
+				}
+				(*out.Title).UnmarshalEasyJSON(in)
+			}
+		case "message":
+			if in.IsNull() {
+				in.Skip()
+				out.Message = nil
+			} else {
+				if out.Message == nil {
+					out.Message = new(FieldChange)
+				}
+				(*out.Message).UnmarshalEasyJSON(in)
+			}
+		case "edited":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Edited).UnmarshalJSON(data))
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -200,73 +228,133 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels2(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels2(out *jwriter.Writer, in User) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels2(out *jwriter.Writer, in UserThreadRevision) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Nickname != "" {
-		const prefix string = ",\"nickname\":"
-		first = false
+	{
+		const prefix string = ",\"thread\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Nickname))
+		out.Int(int(in.Thread))
 	}
-	if in.Fullname != "" {
-		const prefix string = ",\"fullname\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+	{
+		const prefix string = ",\"revision\":"
+		out.RawString(prefix)
+		out.Int(int(in.Revision))
+	}
+	if in.Title != nil {
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		(*in.Title).MarshalEasyJSON(out)
+	}
+	if in.Message != nil {
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		(*in.Message).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"edited\":"
+		out.RawString(prefix)
+		out.Raw((in.Edited).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserThreadRevision) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserThreadRevision) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserThreadRevision) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserThreadRevision) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels2(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels3(in *jlexer.Lexer, out *UserRename) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
 		}
-		out.String(string(in.Fullname))
+		in.Skip()
+		return
 	}
-	if in.Email != "" {
-		const prefix string = ",\"email\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
 		}
-		out.String(string(in.Email))
+		switch key {
+		case "nickname":
+			out.NewNickname = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
 	}
-	if in.About != "" {
-		const prefix string = ",\"about\":"
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels3(out *jwriter.Writer, in UserRename) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"nickname\":"
 		if first {
 			first = false
 			out.RawString(prefix[1:])
 		} else {
 			out.RawString(prefix)
 		}
-		out.String(string(in.About))
+		out.String(string(in.NewNickname))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v User) MarshalJSON() ([]byte, error) {
+func (v UserRename) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels2(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v User) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels2(w, v)
+func (v UserRename) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *User) UnmarshalJSON(data []byte) error {
+func (v *UserRename) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels2(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels2(l, v)
+func (v *UserRename) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels3(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels3(in *jlexer.Lexer, out *ThreadUpdate) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels4(in *jlexer.Lexer, out *UserPostVote) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -285,12 +373,89 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels3(in *
 			continue
 		}
 		switch key {
-		case "title":
-			out.Title = string(in.String())
+		case "post":
+			out.Post = int(in.Int())
+		case "voice":
+			out.Voice = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels4(out *jwriter.Writer, in UserPostVote) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Post))
+	}
+	{
+		const prefix string = ",\"voice\":"
+		out.RawString(prefix)
+		out.Int(int(in.Voice))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserPostVote) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserPostVote) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserPostVote) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserPostVote) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels4(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels5(in *jlexer.Lexer, out *UserPostRevision) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "post":
+			out.Post = int(in.Int())
+		case "revision":
+			out.Revision = int(in.Int())
 		case "message":
 			out.Message = string(in.String())
-		case "thread":
-			out.ThreadID = int(in.Int())
+		case "edited":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Edited).UnmarshalJSON(data))
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -301,14 +466,19 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels3(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels3(out *jwriter.Writer, in ThreadUpdate) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels5(out *jwriter.Writer, in UserPostRevision) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"title\":"
+		const prefix string = ",\"post\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Title))
+		out.Int(int(in.Post))
+	}
+	{
+		const prefix string = ",\"revision\":"
+		out.RawString(prefix)
+		out.Int(int(in.Revision))
 	}
 	{
 		const prefix string = ",\"message\":"
@@ -316,37 +486,1699 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels3(out
 		out.String(string(in.Message))
 	}
 	{
-		const prefix string = ",\"thread\":"
+		const prefix string = ",\"edited\":"
 		out.RawString(prefix)
-		out.Int(int(in.ThreadID))
+		out.Raw((in.Edited).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserPostRevision) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels5(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserPostRevision) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels5(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserPostRevision) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels5(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserPostRevision) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels5(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels6(in *jlexer.Lexer, out *UserPostReaction) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "post":
+			out.Post = int(in.Int())
+		case "reaction":
+			out.Reaction = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels6(out *jwriter.Writer, in UserPostReaction) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Post))
+	}
+	{
+		const prefix string = ",\"reaction\":"
+		out.RawString(prefix)
+		out.String(string(in.Reaction))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserPostReaction) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserPostReaction) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserPostReaction) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserPostReaction) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels6(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels7(in *jlexer.Lexer, out *UserExport) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "user":
+			(out.User).UnmarshalEasyJSON(in)
+		case "aliases":
+			if in.IsNull() {
+				in.Skip()
+				out.Aliases = nil
+			} else {
+				in.Delim('[')
+				if out.Aliases == nil {
+					if !in.IsDelim(']') {
+						out.Aliases = make([]string, 0, 4)
+					} else {
+						out.Aliases = []string{}
+					}
+				} else {
+					out.Aliases = (out.Aliases)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					v1 = string(in.String())
+					out.Aliases = append(out.Aliases, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "forums":
+			if in.IsNull() {
+				in.Skip()
+				out.Forums = nil
+			} else {
+				in.Delim('[')
+				if out.Forums == nil {
+					if !in.IsDelim(']') {
+						out.Forums = make([]Forum, 0, 1)
+					} else {
+						out.Forums = []Forum{}
+					}
+				} else {
+					out.Forums = (out.Forums)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v2 Forum
+					(v2).UnmarshalEasyJSON(in)
+					out.Forums = append(out.Forums, v2)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "memberships":
+			if in.IsNull() {
+				in.Skip()
+				out.Memberships = nil
+			} else {
+				in.Delim('[')
+				if out.Memberships == nil {
+					if !in.IsDelim(']') {
+						out.Memberships = make([]string, 0, 4)
+					} else {
+						out.Memberships = []string{}
+					}
+				} else {
+					out.Memberships = (out.Memberships)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v3 string
+					v3 = string(in.String())
+					out.Memberships = append(out.Memberships, v3)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "threads":
+			if in.IsNull() {
+				in.Skip()
+				out.Threads = nil
+			} else {
+				in.Delim('[')
+				if out.Threads == nil {
+					if !in.IsDelim(']') {
+						out.Threads = make([]Thread, 0, 1)
+					} else {
+						out.Threads = []Thread{}
+					}
+				} else {
+					out.Threads = (out.Threads)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 Thread
+					(v4).UnmarshalEasyJSON(in)
+					out.Threads = append(out.Threads, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "posts":
+			if in.IsNull() {
+				in.Skip()
+				out.Posts = nil
+			} else {
+				in.Delim('[')
+				if out.Posts == nil {
+					if !in.IsDelim(']') {
+						out.Posts = make([]Post, 0, 1)
+					} else {
+						out.Posts = []Post{}
+					}
+				} else {
+					out.Posts = (out.Posts)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v5 Post
+					(v5).UnmarshalEasyJSON(in)
+					out.Posts = append(out.Posts, v5)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "votes":
+			if in.IsNull() {
+				in.Skip()
+				out.Votes = nil
+			} else {
+				in.Delim('[')
+				if out.Votes == nil {
+					if !in.IsDelim(']') {
+						out.Votes = make([]UserVote, 0, 4)
+					} else {
+						out.Votes = []UserVote{}
+					}
+				} else {
+					out.Votes = (out.Votes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v6 UserVote
+					(v6).UnmarshalEasyJSON(in)
+					out.Votes = append(out.Votes, v6)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "postVotes":
+			if in.IsNull() {
+				in.Skip()
+				out.PostVotes = nil
+			} else {
+				in.Delim('[')
+				if out.PostVotes == nil {
+					if !in.IsDelim(']') {
+						out.PostVotes = make([]UserPostVote, 0, 4)
+					} else {
+						out.PostVotes = []UserPostVote{}
+					}
+				} else {
+					out.PostVotes = (out.PostVotes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 UserPostVote
+					(v7).UnmarshalEasyJSON(in)
+					out.PostVotes = append(out.PostVotes, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "reactions":
+			if in.IsNull() {
+				in.Skip()
+				out.Reactions = nil
+			} else {
+				in.Delim('[')
+				if out.Reactions == nil {
+					if !in.IsDelim(']') {
+						out.Reactions = make([]UserPostReaction, 0, 2)
+					} else {
+						out.Reactions = []UserPostReaction{}
+					}
+				} else {
+					out.Reactions = (out.Reactions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v8 UserPostReaction
+					(v8).UnmarshalEasyJSON(in)
+					out.Reactions = append(out.Reactions, v8)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "postRevisions":
+			if in.IsNull() {
+				in.Skip()
+				out.PostRevisions = nil
+			} else {
+				in.Delim('[')
+				if out.PostRevisions == nil {
+					if !in.IsDelim(']') {
+						out.PostRevisions = make([]UserPostRevision, 0, 1)
+					} else {
+						out.PostRevisions = []UserPostRevision{}
+					}
+				} else {
+					out.PostRevisions = (out.PostRevisions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v9 UserPostRevision
+					(v9).UnmarshalEasyJSON(in)
+					out.PostRevisions = append(out.PostRevisions, v9)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "threadRevisions":
+			if in.IsNull() {
+				in.Skip()
+				out.ThreadRevisions = nil
+			} else {
+				in.Delim('[')
+				if out.ThreadRevisions == nil {
+					if !in.IsDelim(']') {
+						out.ThreadRevisions = make([]UserThreadRevision, 0, 1)
+					} else {
+						out.ThreadRevisions = []UserThreadRevision{}
+					}
+				} else {
+					out.ThreadRevisions = (out.ThreadRevisions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 UserThreadRevision
+					(v10).UnmarshalEasyJSON(in)
+					out.ThreadRevisions = append(out.ThreadRevisions, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "subscriptions":
+			if in.IsNull() {
+				in.Skip()
+				out.Subscriptions = nil
+			} else {
+				in.Delim('[')
+				if out.Subscriptions == nil {
+					if !in.IsDelim(']') {
+						out.Subscriptions = make([]Subscription, 0, 1)
+					} else {
+						out.Subscriptions = []Subscription{}
+					}
+				} else {
+					out.Subscriptions = (out.Subscriptions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v11 Subscription
+					(v11).UnmarshalEasyJSON(in)
+					out.Subscriptions = append(out.Subscriptions, v11)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "notifications":
+			if in.IsNull() {
+				in.Skip()
+				out.Notifications = nil
+			} else {
+				in.Delim('[')
+				if out.Notifications == nil {
+					if !in.IsDelim(']') {
+						out.Notifications = make([]Notification, 0, 1)
+					} else {
+						out.Notifications = []Notification{}
+					}
+				} else {
+					out.Notifications = (out.Notifications)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v12 Notification
+					(v12).UnmarshalEasyJSON(in)
+					out.Notifications = append(out.Notifications, v12)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "mentions":
+			if in.IsNull() {
+				in.Skip()
+				out.Mentions = nil
+			} else {
+				in.Delim('[')
+				if out.Mentions == nil {
+					if !in.IsDelim(']') {
+						out.Mentions = make([]Mention, 0, 1)
+					} else {
+						out.Mentions = []Mention{}
+					}
+				} else {
+					out.Mentions = (out.Mentions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 Mention
+					(v13).UnmarshalEasyJSON(in)
+					out.Mentions = append(out.Mentions, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "readMarkers":
+			if in.IsNull() {
+				in.Skip()
+				out.ReadMarkers = nil
+			} else {
+				in.Delim('[')
+				if out.ReadMarkers == nil {
+					if !in.IsDelim(']') {
+						out.ReadMarkers = make([]UserReadMarker, 0, 1)
+					} else {
+						out.ReadMarkers = []UserReadMarker{}
+					}
+				} else {
+					out.ReadMarkers = (out.ReadMarkers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v14 UserReadMarker
+					(v14).UnmarshalEasyJSON(in)
+					out.ReadMarkers = append(out.ReadMarkers, v14)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels7(out *jwriter.Writer, in UserExport) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix[1:])
+		(in.User).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"aliases\":"
+		out.RawString(prefix)
+		if in.Aliases == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v15, v16 := range in.Aliases {
+				if v15 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v16))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"forums\":"
+		out.RawString(prefix)
+		if in.Forums == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v17, v18 := range in.Forums {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				(v18).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"memberships\":"
+		out.RawString(prefix)
+		if in.Memberships == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v19, v20 := range in.Memberships {
+				if v19 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v20))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"threads\":"
+		out.RawString(prefix)
+		if in.Threads == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v21, v22 := range in.Threads {
+				if v21 > 0 {
+					out.RawByte(',')
+				}
+				(v22).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix)
+		if in.Posts == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v23, v24 := range in.Posts {
+				if v23 > 0 {
+					out.RawByte(',')
+				}
+				(v24).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"votes\":"
+		out.RawString(prefix)
+		if in.Votes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v25, v26 := range in.Votes {
+				if v25 > 0 {
+					out.RawByte(',')
+				}
+				(v26).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"postVotes\":"
+		out.RawString(prefix)
+		if in.PostVotes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v27, v28 := range in.PostVotes {
+				if v27 > 0 {
+					out.RawByte(',')
+				}
+				(v28).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"reactions\":"
+		out.RawString(prefix)
+		if in.Reactions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v29, v30 := range in.Reactions {
+				if v29 > 0 {
+					out.RawByte(',')
+				}
+				(v30).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"postRevisions\":"
+		out.RawString(prefix)
+		if in.PostRevisions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v31, v32 := range in.PostRevisions {
+				if v31 > 0 {
+					out.RawByte(',')
+				}
+				(v32).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"threadRevisions\":"
+		out.RawString(prefix)
+		if in.ThreadRevisions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v33, v34 := range in.ThreadRevisions {
+				if v33 > 0 {
+					out.RawByte(',')
+				}
+				(v34).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"subscriptions\":"
+		out.RawString(prefix)
+		if in.Subscriptions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v35, v36 := range in.Subscriptions {
+				if v35 > 0 {
+					out.RawByte(',')
+				}
+				(v36).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"notifications\":"
+		out.RawString(prefix)
+		if in.Notifications == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v37, v38 := range in.Notifications {
+				if v37 > 0 {
+					out.RawByte(',')
+				}
+				(v38).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"mentions\":"
+		out.RawString(prefix)
+		if in.Mentions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v39, v40 := range in.Mentions {
+				if v39 > 0 {
+					out.RawByte(',')
+				}
+				(v40).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"readMarkers\":"
+		out.RawString(prefix)
+		if in.ReadMarkers == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v41, v42 := range in.ReadMarkers {
+				if v41 > 0 {
+					out.RawByte(',')
+				}
+				(v42).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserExport) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserExport) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserExport) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserExport) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels7(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels8(in *jlexer.Lexer, out *UserDeletion) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "mode":
+			out.Mode = string(in.String())
+		case "threads":
+			out.Threads = int(in.Int())
+		case "posts":
+			out.Posts = int(in.Int())
+		case "votes":
+			out.Votes = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels8(out *jwriter.Writer, in UserDeletion) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Nickname))
+	}
+	{
+		const prefix string = ",\"mode\":"
+		out.RawString(prefix)
+		out.String(string(in.Mode))
+	}
+	{
+		const prefix string = ",\"threads\":"
+		out.RawString(prefix)
+		out.Int(int(in.Threads))
+	}
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix)
+		out.Int(int(in.Posts))
+	}
+	{
+		const prefix string = ",\"votes\":"
+		out.RawString(prefix)
+		out.Int(int(in.Votes))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserDeletion) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserDeletion) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserDeletion) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserDeletion) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels8(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels9(in *jlexer.Lexer, out *UserCounters) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "posts":
+			out.Posts = int(in.Int())
+		case "threads":
+			out.Threads = int(in.Int())
+		case "votes":
+			out.Votes = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels9(out *jwriter.Writer, in UserCounters) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Posts))
+	}
+	{
+		const prefix string = ",\"threads\":"
+		out.RawString(prefix)
+		out.Int(int(in.Threads))
+	}
+	{
+		const prefix string = ",\"votes\":"
+		out.RawString(prefix)
+		out.Int(int(in.Votes))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserCounters) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserCounters) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserCounters) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserCounters) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels9(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels10(in *jlexer.Lexer, out *User) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "fullname":
+			out.Fullname = string(in.String())
+		case "email":
+			out.Email = string(in.String())
+		case "about":
+			out.About = string(in.String())
+		case "counters":
+			if in.IsNull() {
+				in.Skip()
+				out.Counters = nil
+			} else {
+				if out.Counters == nil {
+					out.Counters = new(UserCounters)
+				}
+				(*out.Counters).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels10(out *jwriter.Writer, in User) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Nickname != "" {
+		const prefix string = ",\"nickname\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Nickname))
+	}
+	if in.Fullname != "" {
+		const prefix string = ",\"fullname\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Fullname))
+	}
+	if in.Email != "" {
+		const prefix string = ",\"email\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Email))
+	}
+	if in.About != "" {
+		const prefix string = ",\"about\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.About))
+	}
+	if in.Counters != nil {
+		const prefix string = ",\"counters\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Counters).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v User) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v User) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *User) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels10(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels11(in *jlexer.Lexer, out *ThreadUpdate) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "title":
+			out.Title = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		case "editor":
+			out.Editor = string(in.String())
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v43 string
+					v43 = string(in.String())
+					out.Tags = append(out.Tags, v43)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "thread":
+			out.ThreadID = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels11(out *jwriter.Writer, in ThreadUpdate) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	if in.Editor != "" {
+		const prefix string = ",\"editor\":"
+		out.RawString(prefix)
+		out.String(string(in.Editor))
+	}
+	{
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		if in.Tags == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v44, v45 := range in.Tags {
+				if v44 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v45))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int(int(in.ThreadID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadUpdate) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadUpdate) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadUpdate) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels11(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels12(in *jlexer.Lexer, out *ThreadState) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "state":
+			out.State = string(in.String())
+		case "thread":
+			out.ThreadID = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels12(out *jwriter.Writer, in ThreadState) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"state\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.State))
+	}
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int(int(in.ThreadID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadState) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels12(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadState) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels12(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadState) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels12(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadState) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels12(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels13(in *jlexer.Lexer, out *ThreadRevision) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "revision":
+			out.Revision = int(in.Int())
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+				out.Title = nil
+			} else {
+				if out.Title == nil {
+					out.Title = new(FieldChange)
+				}
+				(*out.Title).UnmarshalEasyJSON(in)
+			}
+		case "message":
+			if in.IsNull() {
+				in.Skip()
+				out.Message = nil
+			} else {
+				if out.Message == nil {
+					out.Message = new(FieldChange)
+				}
+				(*out.Message).UnmarshalEasyJSON(in)
+			}
+		case "editor":
+			out.Editor = string(in.String())
+		case "edited":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Edited).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels13(out *jwriter.Writer, in ThreadRevision) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"revision\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Revision))
+	}
+	if in.Title != nil {
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		(*in.Title).MarshalEasyJSON(out)
+	}
+	if in.Message != nil {
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		(*in.Message).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"editor\":"
+		out.RawString(prefix)
+		out.String(string(in.Editor))
+	}
+	{
+		const prefix string = ",\"edited\":"
+		out.RawString(prefix)
+		out.Raw((in.Edited).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadRevision) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels13(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadRevision) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels13(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadRevision) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels13(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadRevision) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels13(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels14(in *jlexer.Lexer, out *ThreadInput) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "thread":
+			out.ThreadID = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels14(out *jwriter.Writer, in ThreadInput) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.ThreadID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadInput) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels14(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadInput) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels14(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadInput) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels14(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels14(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels15(in *jlexer.Lexer, out *Thread) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "author":
+			out.Author = string(in.String())
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
+			}
+		case "forum":
+			out.Forum = string(in.String())
+		case "id":
+			out.ID = int(in.Int())
+		case "message":
+			out.Message = string(in.String())
+		case "slug":
+			out.Slug = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "votes":
+			out.Votes = int(in.Int())
+		case "state":
+			out.State = string(in.String())
+		case "edited":
+			out.IsEdited = bool(in.Bool())
+		case "editedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.EditedAt = nil
+			} else {
+				if out.EditedAt == nil {
+					out.EditedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.EditedAt).UnmarshalJSON(data))
+				}
+			}
+		case "unknownMentions":
+			if in.IsNull() {
+				in.Skip()
+				out.UnknownMentions = nil
+			} else {
+				in.Delim('[')
+				if out.UnknownMentions == nil {
+					if !in.IsDelim(']') {
+						out.UnknownMentions = make([]string, 0, 4)
+					} else {
+						out.UnknownMentions = []string{}
+					}
+				} else {
+					out.UnknownMentions = (out.UnknownMentions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v46 string
+					v46 = string(in.String())
+					out.UnknownMentions = append(out.UnknownMentions, v46)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "unread":
+			if in.IsNull() {
+				in.Skip()
+				out.Unread = nil
+			} else {
+				if out.Unread == nil {
+					out.Unread = new(int)
+				}
+				*out.Unread = int(in.Int())
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v47 string
+					v47 = string(in.String())
+					out.Tags = append(out.Tags, v47)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels15(out *jwriter.Writer, in Thread) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Author != "" {
+		const prefix string = ",\"author\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Author))
+	}
+	if true {
+		const prefix string = ",\"created\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.Created).MarshalJSON())
+	}
+	if in.Forum != "" {
+		const prefix string = ",\"forum\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Forum))
+	}
+	if in.ID != 0 {
+		const prefix string = ",\"id\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.ID))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Message))
+	}
+	if in.Slug != "" {
+		const prefix string = ",\"slug\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Slug))
+	}
+	if in.Title != "" {
+		const prefix string = ",\"title\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Title))
+	}
+	if in.Votes != 0 {
+		const prefix string = ",\"votes\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Votes))
+	}
+	if in.State != "" {
+		const prefix string = ",\"state\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.State))
+	}
+	if in.IsEdited {
+		const prefix string = ",\"edited\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.IsEdited))
+	}
+	if in.EditedAt != nil {
+		const prefix string = ",\"editedAt\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((*in.EditedAt).MarshalJSON())
+	}
+	if len(in.UnknownMentions) != 0 {
+		const prefix string = ",\"unknownMentions\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v48, v49 := range in.UnknownMentions {
+				if v48 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v49))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Unread != nil {
+		const prefix string = ",\"unread\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.Unread))
+	}
+	if len(in.Tags) != 0 {
+		const prefix string = ",\"tags\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v50, v51 := range in.Tags {
+				if v50 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v51))
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ThreadUpdate) MarshalJSON() ([]byte, error) {
+func (v Thread) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels3(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels15(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ThreadUpdate) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels3(w, v)
+func (v Thread) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels15(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ThreadUpdate) UnmarshalJSON(data []byte) error {
+func (v *Thread) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels3(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels15(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ThreadUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels3(l, v)
+func (v *Thread) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels15(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels4(in *jlexer.Lexer, out *ThreadInput) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels16(in *jlexer.Lexer, out *Status) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -365,8 +2197,14 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels4(in *
 			continue
 		}
 		switch key {
+		case "forum":
+			out.Forum = int32(in.Int32())
+		case "post":
+			out.Post = int64(in.Int64())
 		case "thread":
-			out.ThreadID = int(in.Int())
+			out.Thread = int32(in.Int32())
+		case "user":
+			out.User = int32(in.Int32())
 		default:
 			in.SkipRecursive()
 		}
@@ -377,42 +2215,57 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels4(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels4(out *jwriter.Writer, in ThreadInput) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels16(out *jwriter.Writer, in Status) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"thread\":"
+		const prefix string = ",\"forum\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.ThreadID))
+		out.Int32(int32(in.Forum))
+	}
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Post))
+	}
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Thread))
+	}
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.User))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ThreadInput) MarshalJSON() ([]byte, error) {
+func (v Status) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels4(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels16(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ThreadInput) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels4(w, v)
+func (v Status) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels16(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ThreadInput) UnmarshalJSON(data []byte) error {
+func (v *Status) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels4(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels16(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ThreadInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels4(l, v)
+func (v *Status) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels16(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels5(in *jlexer.Lexer, out *ThreadGetPosts) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels17(in *jlexer.Lexer, out *RespError) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -431,16 +2284,35 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels5(in *
 			continue
 		}
 		switch key {
-		case "Limit":
-			out.Limit = int(in.Int())
-		case "Since":
-			out.Since = int(in.Int())
-		case "Sort":
-			out.Sort = string(in.String())
-		case "Desc":
-			out.Desc = bool(in.Bool())
-		case "thread":
-			out.ThreadID = int(in.Int())
+		case "message":
+			out.Message = string(in.String())
+		case "entity":
+			out.Entity = string(in.String())
+		case "reason":
+			out.Reason = string(in.String())
+		case "fields":
+			if in.IsNull() {
+				in.Skip()
+				out.Fields = nil
+			} else {
+				in.Delim('[')
+				if out.Fields == nil {
+					if !in.IsDelim(']') {
+						out.Fields = make([]FieldError, 0, 2)
+					} else {
+						out.Fields = []FieldError{}
+					}
+				} else {
+					out.Fields = (out.Fields)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v52 FieldError
+					(v52).UnmarshalEasyJSON(in)
+					out.Fields = append(out.Fields, v52)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -451,62 +2323,66 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels5(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels5(out *jwriter.Writer, in ThreadGetPosts) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels17(out *jwriter.Writer, in RespError) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Limit\":"
+		const prefix string = ",\"message\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Limit))
-	}
-	{
-		const prefix string = ",\"Since\":"
-		out.RawString(prefix)
-		out.Int(int(in.Since))
+		out.String(string(in.Message))
 	}
-	{
-		const prefix string = ",\"Sort\":"
+	if in.Entity != "" {
+		const prefix string = ",\"entity\":"
 		out.RawString(prefix)
-		out.String(string(in.Sort))
+		out.String(string(in.Entity))
 	}
-	{
-		const prefix string = ",\"Desc\":"
+	if in.Reason != "" {
+		const prefix string = ",\"reason\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.Desc))
+		out.String(string(in.Reason))
 	}
-	{
-		const prefix string = ",\"thread\":"
+	if len(in.Fields) != 0 {
+		const prefix string = ",\"fields\":"
 		out.RawString(prefix)
-		out.Int(int(in.ThreadID))
+		{
+			out.RawByte('[')
+			for v53, v54 := range in.Fields {
+				if v53 > 0 {
+					out.RawByte(',')
+				}
+				(v54).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ThreadGetPosts) MarshalJSON() ([]byte, error) {
+func (v RespError) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels5(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels17(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ThreadGetPosts) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels5(w, v)
+func (v RespError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels17(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ThreadGetPosts) UnmarshalJSON(data []byte) error {
+func (v *RespError) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels5(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels17(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ThreadGetPosts) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels5(l, v)
+func (v *RespError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels17(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels6(in *jlexer.Lexer, out *Thread) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels18(in *jlexer.Lexer, out *PostsPurged) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -525,24 +2401,8 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels6(in *
 			continue
 		}
 		switch key {
-		case "author":
-			out.Author = string(in.String())
-		case "created":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Created).UnmarshalJSON(data))
-			}
-		case "forum":
-			out.Forum = string(in.String())
-		case "id":
-			out.ID = int(in.Int())
-		case "message":
-			out.Message = string(in.String())
-		case "slug":
-			out.Slug = string(in.String())
-		case "title":
-			out.Title = string(in.String())
-		case "votes":
-			out.Votes = int(in.Int())
+		case "posts":
+			out.Posts = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -553,113 +2413,42 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels6(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels6(out *jwriter.Writer, in Thread) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels18(out *jwriter.Writer, in PostsPurged) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Author != "" {
-		const prefix string = ",\"author\":"
-		first = false
+	{
+		const prefix string = ",\"posts\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Author))
-	}
-	if true {
-		const prefix string = ",\"created\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Raw((in.Created).MarshalJSON())
-	}
-	if in.Forum != "" {
-		const prefix string = ",\"forum\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Forum))
-	}
-	if in.ID != 0 {
-		const prefix string = ",\"id\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Int(int(in.ID))
-	}
-	if in.Message != "" {
-		const prefix string = ",\"message\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Message))
-	}
-	if in.Slug != "" {
-		const prefix string = ",\"slug\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Slug))
-	}
-	if in.Title != "" {
-		const prefix string = ",\"title\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Title))
-	}
-	if in.Votes != 0 {
-		const prefix string = ",\"votes\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.Int(int(in.Votes))
+		out.Int(int(in.Posts))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Thread) MarshalJSON() ([]byte, error) {
+func (v PostsPurged) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels6(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels18(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Thread) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels6(w, v)
+func (v PostsPurged) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels18(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Thread) UnmarshalJSON(data []byte) error {
+func (v *PostsPurged) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels6(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels18(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Thread) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels6(l, v)
+func (v *PostsPurged) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels18(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels7(in *jlexer.Lexer, out *Status) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels19(in *jlexer.Lexer, out *PostVote) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -678,14 +2467,10 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels7(in *
 			continue
 		}
 		switch key {
-		case "forum":
-			out.Forum = int32(in.Int32())
-		case "post":
-			out.Post = int64(in.Int64())
-		case "thread":
-			out.Thread = int32(in.Int32())
-		case "user":
-			out.User = int32(in.Int32())
+		case "nickname":
+			out.User = string(in.String())
+		case "voice":
+			out.Voice = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -696,57 +2481,47 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels7(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels7(out *jwriter.Writer, in Status) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels19(out *jwriter.Writer, in PostVote) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"forum\":"
+		const prefix string = ",\"nickname\":"
 		out.RawString(prefix[1:])
-		out.Int32(int32(in.Forum))
-	}
-	{
-		const prefix string = ",\"post\":"
-		out.RawString(prefix)
-		out.Int64(int64(in.Post))
-	}
-	{
-		const prefix string = ",\"thread\":"
-		out.RawString(prefix)
-		out.Int32(int32(in.Thread))
+		out.String(string(in.User))
 	}
 	{
-		const prefix string = ",\"user\":"
+		const prefix string = ",\"voice\":"
 		out.RawString(prefix)
-		out.Int32(int32(in.User))
+		out.Int(int(in.Voice))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Status) MarshalJSON() ([]byte, error) {
+func (v PostVote) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels7(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels19(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Status) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels7(w, v)
+func (v PostVote) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels19(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Status) UnmarshalJSON(data []byte) error {
+func (v *PostVote) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels7(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels19(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Status) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels7(l, v)
+func (v *PostVote) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels19(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels8(in *jlexer.Lexer, out *RespError) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels20(in *jlexer.Lexer, out *PostUpdate) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -765,8 +2540,12 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels8(in *
 			continue
 		}
 		switch key {
+		case "id":
+			out.ID = int(in.Int())
 		case "message":
 			out.Message = string(in.String())
+		case "editor":
+			out.Editor = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -777,42 +2556,52 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels8(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels8(out *jwriter.Writer, in RespError) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels20(out *jwriter.Writer, in PostUpdate) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"message\":"
+		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
+		out.Int(int(in.ID))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
 		out.String(string(in.Message))
 	}
+	if in.Editor != "" {
+		const prefix string = ",\"editor\":"
+		out.RawString(prefix)
+		out.String(string(in.Editor))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v RespError) MarshalJSON() ([]byte, error) {
+func (v PostUpdate) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels8(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels20(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v RespError) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels8(w, v)
+func (v PostUpdate) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels20(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *RespError) UnmarshalJSON(data []byte) error {
+func (v *PostUpdate) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels8(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels20(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *RespError) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels8(l, v)
+func (v *PostUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels20(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels9(in *jlexer.Lexer, out *PostUpdate) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels21(in *jlexer.Lexer, out *PostRevision) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -831,10 +2620,39 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels9(in *
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = int(in.Int())
+		case "revision":
+			out.Revision = int(in.Int())
 		case "message":
 			out.Message = string(in.String())
+		case "editor":
+			out.Editor = string(in.String())
+		case "edited":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Edited).UnmarshalJSON(data))
+			}
+		case "diff":
+			if in.IsNull() {
+				in.Skip()
+				out.Diff = nil
+			} else {
+				in.Delim('[')
+				if out.Diff == nil {
+					if !in.IsDelim(']') {
+						out.Diff = make([]DiffLine, 0, 2)
+					} else {
+						out.Diff = []DiffLine{}
+					}
+				} else {
+					out.Diff = (out.Diff)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v55 DiffLine
+					(v55).UnmarshalEasyJSON(in)
+					out.Diff = append(out.Diff, v55)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -845,47 +2663,73 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels9(in *
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels9(out *jwriter.Writer, in PostUpdate) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels21(out *jwriter.Writer, in PostRevision) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"id\":"
+		const prefix string = ",\"revision\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.ID))
+		out.Int(int(in.Revision))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	{
+		const prefix string = ",\"editor\":"
+		out.RawString(prefix)
+		out.String(string(in.Editor))
+	}
+	{
+		const prefix string = ",\"edited\":"
+		out.RawString(prefix)
+		out.Raw((in.Edited).MarshalJSON())
 	}
 	{
-		const prefix string = ",\"message\":"
+		const prefix string = ",\"diff\":"
 		out.RawString(prefix)
-		out.String(string(in.Message))
+		if in.Diff == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v56, v57 := range in.Diff {
+				if v56 > 0 {
+					out.RawByte(',')
+				}
+				(v57).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PostUpdate) MarshalJSON() ([]byte, error) {
+func (v PostRevision) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels9(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels21(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PostUpdate) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels9(w, v)
+func (v PostRevision) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels21(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PostUpdate) UnmarshalJSON(data []byte) error {
+func (v *PostRevision) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels9(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels21(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PostUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels9(l, v)
+func (v *PostRevision) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels21(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels10(in *jlexer.Lexer, out *PostInput) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels22(in *jlexer.Lexer, out *PostReaction) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -904,8 +2748,10 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels10(in
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = int(in.Int())
+		case "nickname":
+			out.User = string(in.String())
+		case "reaction":
+			out.Reaction = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -916,42 +2762,47 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels10(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels10(out *jwriter.Writer, in PostInput) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels22(out *jwriter.Writer, in PostReaction) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"id\":"
+		const prefix string = ",\"nickname\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.ID))
+		out.String(string(in.User))
+	}
+	{
+		const prefix string = ",\"reaction\":"
+		out.RawString(prefix)
+		out.String(string(in.Reaction))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PostInput) MarshalJSON() ([]byte, error) {
+func (v PostReaction) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels10(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels22(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PostInput) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels10(w, v)
+func (v PostReaction) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels22(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PostInput) UnmarshalJSON(data []byte) error {
+func (v *PostReaction) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels10(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels22(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PostInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels10(l, v)
+func (v *PostReaction) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels22(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels11(in *jlexer.Lexer, out *PostFull) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels23(in *jlexer.Lexer, out *PostFull) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1010,6 +2861,29 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels11(in
 				}
 				(*out.Thread).UnmarshalEasyJSON(in)
 			}
+		case "history":
+			if in.IsNull() {
+				in.Skip()
+				out.History = nil
+			} else {
+				in.Delim('[')
+				if out.History == nil {
+					if !in.IsDelim(']') {
+						out.History = make([]PostRevision, 0, 1)
+					} else {
+						out.History = []PostRevision{}
+					}
+				} else {
+					out.History = (out.History)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v58 PostRevision
+					(v58).UnmarshalEasyJSON(in)
+					out.History = append(out.History, v58)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -1020,7 +2894,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels11(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels11(out *jwriter.Writer, in PostFull) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels23(out *jwriter.Writer, in PostFull) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1060,33 +2934,52 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels11(out
 		}
 		(*in.Thread).MarshalEasyJSON(out)
 	}
+	if len(in.History) != 0 {
+		const prefix string = ",\"history\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v59, v60 := range in.History {
+				if v59 > 0 {
+					out.RawByte(',')
+				}
+				(v60).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v PostFull) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels11(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels23(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v PostFull) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels11(w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels23(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *PostFull) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels11(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels23(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *PostFull) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels11(l, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels23(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels12(in *jlexer.Lexer, out *PostCreate) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels24(in *jlexer.Lexer, out *PostCreate) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1121,7 +3014,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels12(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels12(out *jwriter.Writer, in PostCreate) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels24(out *jwriter.Writer, in PostCreate) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1157,27 +3050,27 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels12(out
 // MarshalJSON supports json.Marshaler interface
 func (v PostCreate) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels12(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels24(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v PostCreate) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels12(w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels24(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *PostCreate) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels12(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels24(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *PostCreate) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels12(l, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels24(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels13(in *jlexer.Lexer, out *Post) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels25(in *jlexer.Lexer, out *Post) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1210,6 +3103,53 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels13(in
 			out.Forum = string(in.String())
 		case "created":
 			out.Created = string(in.String())
+		case "isDeleted":
+			out.IsDeleted = bool(in.Bool())
+		case "score":
+			out.Score = int(in.Int())
+		case "reactions":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Reactions = make(map[string]int)
+				} else {
+					out.Reactions = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v61 int
+					v61 = int(in.Int())
+					(out.Reactions)[key] = v61
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "unknownMentions":
+			if in.IsNull() {
+				in.Skip()
+				out.UnknownMentions = nil
+			} else {
+				in.Delim('[')
+				if out.UnknownMentions == nil {
+					if !in.IsDelim(']') {
+						out.UnknownMentions = make([]string, 0, 4)
+					} else {
+						out.UnknownMentions = []string{}
+					}
+				} else {
+					out.UnknownMentions = (out.UnknownMentions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v62 string
+					v62 = string(in.String())
+					out.UnknownMentions = append(out.UnknownMentions, v62)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		case "thread":
 			out.ThreadID = int(in.Int())
 		default:
@@ -1222,7 +3162,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels13(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels13(out *jwriter.Writer, in Post) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels25(out *jwriter.Writer, in Post) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1292,6 +3232,69 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels13(out
 		}
 		out.String(string(in.Created))
 	}
+	if in.IsDeleted {
+		const prefix string = ",\"isDeleted\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.IsDeleted))
+	}
+	if in.Score != 0 {
+		const prefix string = ",\"score\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Score))
+	}
+	if len(in.Reactions) != 0 {
+		const prefix string = ",\"reactions\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('{')
+			v63First := true
+			for v63Name, v63Value := range in.Reactions {
+				if v63First {
+					v63First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v63Name))
+				out.RawByte(':')
+				out.Int(int(v63Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if len(in.UnknownMentions) != 0 {
+		const prefix string = ",\"unknownMentions\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v64, v65 := range in.UnknownMentions {
+				if v64 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v65))
+			}
+			out.RawByte(']')
+		}
+	}
 	{
 		const prefix string = ",\"thread\":"
 		if first {
@@ -1308,27 +3311,27 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels13(out
 // MarshalJSON supports json.Marshaler interface
 func (v Post) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels13(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels25(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Post) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels13(w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels25(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Post) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels13(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels25(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Post) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels13(l, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels25(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels14(in *jlexer.Lexer, out *ForumInput) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels26(in *jlexer.Lexer, out *ForumUpdate) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1347,8 +3350,22 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels14(in
 			continue
 		}
 		switch key {
-		case "Slug":
-			out.Slug = string(in.String())
+		case "slug":
+			out.NewSlug = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "parent":
+			if in.IsNull() {
+				in.Skip()
+				out.Parent = nil
+			} else {
+				if out.Parent == nil {
+					out.Parent = new(string)
+				}
+				*out.Parent = string(in.String())
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -1359,42 +3376,66 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels14(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels14(out *jwriter.Writer, in ForumInput) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels26(out *jwriter.Writer, in ForumUpdate) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Slug\":"
-		out.RawString(prefix[1:])
-		out.String(string(in.Slug))
+		const prefix string = ",\"slug\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.NewSlug))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix)
+		out.String(string(in.User))
+	}
+	{
+		const prefix string = ",\"parent\":"
+		out.RawString(prefix)
+		if in.Parent == nil {
+			out.RawString("null")
+		} else {
+			out.String(string(*in.Parent))
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ForumInput) MarshalJSON() ([]byte, error) {
+func (v ForumUpdate) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels14(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels26(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ForumInput) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels14(w, v)
+func (v ForumUpdate) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels26(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ForumInput) UnmarshalJSON(data []byte) error {
+func (v *ForumUpdate) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels14(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels26(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ForumInput) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels14(l, v)
+func (v *ForumUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels26(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels15(in *jlexer.Lexer, out *ForumGetUsers) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels27(in *jlexer.Lexer, out *ForumTotals) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1413,14 +3454,12 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels15(in
 			continue
 		}
 		switch key {
-		case "Slug":
-			out.Slug = string(in.String())
-		case "Limit":
-			out.Limit = int(in.Int())
-		case "Since":
-			out.Since = string(in.String())
-		case "Desc":
-			out.Desc = bool(in.Bool())
+		case "threads":
+			out.Threads = int(in.Int())
+		case "posts":
+			out.Posts = int(in.Int())
+		case "subforums":
+			out.Subforums = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -1431,57 +3470,52 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels15(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels15(out *jwriter.Writer, in ForumGetUsers) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels27(out *jwriter.Writer, in ForumTotals) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Slug\":"
+		const prefix string = ",\"threads\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Slug))
-	}
-	{
-		const prefix string = ",\"Limit\":"
-		out.RawString(prefix)
-		out.Int(int(in.Limit))
+		out.Int(int(in.Threads))
 	}
 	{
-		const prefix string = ",\"Since\":"
+		const prefix string = ",\"posts\":"
 		out.RawString(prefix)
-		out.String(string(in.Since))
+		out.Int(int(in.Posts))
 	}
 	{
-		const prefix string = ",\"Desc\":"
+		const prefix string = ",\"subforums\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.Desc))
+		out.Int(int(in.Subforums))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ForumGetUsers) MarshalJSON() ([]byte, error) {
+func (v ForumTotals) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels15(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels27(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ForumGetUsers) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels15(w, v)
+func (v ForumTotals) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels27(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ForumGetUsers) UnmarshalJSON(data []byte) error {
+func (v *ForumTotals) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels15(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels27(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ForumGetUsers) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels15(l, v)
+func (v *ForumTotals) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels27(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels16(in *jlexer.Lexer, out *ForumGetThreads) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels28(in *jlexer.Lexer, out *ForumDeletion) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1500,14 +3534,22 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels16(in
 			continue
 		}
 		switch key {
-		case "Slug":
-			out.Slug = string(in.String())
-		case "Limit":
-			out.Limit = int(in.Int())
-		case "Since":
-			out.Since = string(in.String())
-		case "Desc":
-			out.Desc = bool(in.Bool())
+		case "forum":
+			out.Forum = string(in.String())
+		case "threads":
+			out.Threads = int(in.Int())
+		case "posts":
+			out.Posts = int(in.Int())
+		case "votes":
+			out.Votes = int(in.Int())
+		case "postVotes":
+			out.PostVotes = int(in.Int())
+		case "users":
+			out.Users = int(in.Int())
+		case "aliases":
+			out.Aliases = int(in.Int())
+		case "dryRun":
+			out.DryRun = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -1518,57 +3560,77 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels16(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels16(out *jwriter.Writer, in ForumGetThreads) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels28(out *jwriter.Writer, in ForumDeletion) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Slug\":"
+		const prefix string = ",\"forum\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Slug))
+		out.String(string(in.Forum))
+	}
+	{
+		const prefix string = ",\"threads\":"
+		out.RawString(prefix)
+		out.Int(int(in.Threads))
+	}
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix)
+		out.Int(int(in.Posts))
+	}
+	{
+		const prefix string = ",\"votes\":"
+		out.RawString(prefix)
+		out.Int(int(in.Votes))
+	}
+	{
+		const prefix string = ",\"postVotes\":"
+		out.RawString(prefix)
+		out.Int(int(in.PostVotes))
 	}
 	{
-		const prefix string = ",\"Limit\":"
+		const prefix string = ",\"users\":"
 		out.RawString(prefix)
-		out.Int(int(in.Limit))
+		out.Int(int(in.Users))
 	}
 	{
-		const prefix string = ",\"Since\":"
+		const prefix string = ",\"aliases\":"
 		out.RawString(prefix)
-		out.String(string(in.Since))
+		out.Int(int(in.Aliases))
 	}
 	{
-		const prefix string = ",\"Desc\":"
+		const prefix string = ",\"dryRun\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.Desc))
+		out.Bool(bool(in.DryRun))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ForumGetThreads) MarshalJSON() ([]byte, error) {
+func (v ForumDeletion) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels16(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels28(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ForumGetThreads) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels16(w, v)
+func (v ForumDeletion) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels28(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ForumGetThreads) UnmarshalJSON(data []byte) error {
+func (v *ForumDeletion) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels16(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels28(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ForumGetThreads) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels16(l, v)
+func (v *ForumDeletion) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels28(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels17(in *jlexer.Lexer, out *ForumCreate) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels29(in *jlexer.Lexer, out *ForumCreate) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1593,6 +3655,8 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels17(in
 			out.Title = string(in.String())
 		case "user":
 			out.User = string(in.String())
+		case "parent":
+			out.Parent = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1603,7 +3667,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels17(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels17(out *jwriter.Writer, in ForumCreate) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels29(out *jwriter.Writer, in ForumCreate) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1622,33 +3686,38 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels17(out
 		out.RawString(prefix)
 		out.String(string(in.User))
 	}
+	if in.Parent != "" {
+		const prefix string = ",\"parent\":"
+		out.RawString(prefix)
+		out.String(string(in.Parent))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v ForumCreate) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels17(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels29(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v ForumCreate) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels17(w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels29(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *ForumCreate) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels17(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels29(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *ForumCreate) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels17(l, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels29(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels18(in *jlexer.Lexer, out *Forum) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels30(in *jlexer.Lexer, out *Forum) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1677,6 +3746,18 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels18(in
 			out.Threads = int(in.Int())
 		case "posts":
 			out.Posts = int(in.Int())
+		case "parent":
+			out.Parent = string(in.String())
+		case "totals":
+			if in.IsNull() {
+				in.Skip()
+				out.Totals = nil
+			} else {
+				if out.Totals == nil {
+					out.Totals = new(ForumTotals)
+				}
+				(*out.Totals).UnmarshalEasyJSON(in)
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -1687,7 +3768,7 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels18(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels18(out *jwriter.Writer, in Forum) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels30(out *jwriter.Writer, in Forum) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -1737,33 +3818,53 @@ func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels18(out
 		}
 		out.Int(int(in.Posts))
 	}
+	if in.Parent != "" {
+		const prefix string = ",\"parent\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Parent))
+	}
+	if in.Totals != nil {
+		const prefix string = ",\"totals\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Totals).MarshalEasyJSON(out)
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
 func (v Forum) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels18(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels30(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v Forum) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels18(w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels30(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
 func (v *Forum) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels18(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels30(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *Forum) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels18(l, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels30(l, v)
 }
-func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels19(in *jlexer.Lexer, out *Error) {
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels31(in *jlexer.Lexer, out *FieldChange) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1782,8 +3883,10 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels19(in
 			continue
 		}
 		switch key {
-		case "message":
-			out.Message = string(in.String())
+		case "old":
+			out.Old = string(in.String())
+		case "new":
+			out.New = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1794,43 +3897,116 @@ func easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels19(in
 		in.Consumed()
 	}
 }
-func easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels19(out *jwriter.Writer, in Error) {
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels31(out *jwriter.Writer, in FieldChange) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"message\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+		const prefix string = ",\"old\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Old))
+	}
+	{
+		const prefix string = ",\"new\":"
+		out.RawString(prefix)
+		out.String(string(in.New))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FieldChange) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels31(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FieldChange) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels31(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FieldChange) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels31(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FieldChange) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels31(l, v)
+}
+func easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels32(in *jlexer.Lexer, out *DiffLine) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
 		}
-		out.String(string(in.Message))
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "op":
+			out.Op = string(in.String())
+		case "text":
+			out.Text = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels32(out *jwriter.Writer, in DiffLine) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"op\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Op))
+	}
+	{
+		const prefix string = ",\"text\":"
+		out.RawString(prefix)
+		out.String(string(in.Text))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Error) MarshalJSON() ([]byte, error) {
+func (v DiffLine) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels19(&w, v)
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels32(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Error) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonC8d74561EncodeGithubComPringleskateTPDBHomeworkInternalModels19(w, v)
+func (v DiffLine) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonC8d74561EncodeGithubComEgorAistTPDBProjectInternalModels32(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Error) UnmarshalJSON(data []byte) error {
+func (v *DiffLine) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels19(&r, v)
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels32(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Error) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonC8d74561DecodeGithubComPringleskateTPDBHomeworkInternalModels19(l, v)
+func (v *DiffLine) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonC8d74561DecodeGithubComEgorAistTPDBProjectInternalModels32(l, v)
 }
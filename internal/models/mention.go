@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+//easyjson:json
+type Mention struct {
+	ID      int       `json:"id"`
+	Forum   string    `json:"forum"`
+	Thread  int       `json:"thread"`
+	Post    int       `json:"post,omitempty"` // Нет, если упоминание в самой ветке.
+	Author  string    `json:"author"`         // Кто упомянул пользователя.
+	Created time.Time `json:"created"`
+}
+
+type UserGetMentions struct {
+	Nickname string
+	Limit    int
+	Since    int
+	Desc     bool
+}
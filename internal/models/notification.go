@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+// Kinds of notifications.
+const (
+	NotifyPost   = "post"   // A new post in a subscribed thread.
+	NotifyReply  = "reply"  // A reply to a post of the user.
+	NotifyThread = "thread" // A new thread in a subscribed forum.
+	NotifyVote   = "vote"   // A vote for a thread of the user.
+)
+
+//easyjson:json
+type Subscription struct {
+	User   string `json:"nickname"`
+	Thread int    `json:"thread,omitempty"`
+	Forum  string `json:"forum,omitempty"`
+}
+
+//easyjson:json
+type Notification struct {
+	ID      int       `json:"id"`
+	Kind    string    `json:"kind"`
+	Thread  int       `json:"thread"`
+	Post    int       `json:"post,omitempty"`
+	Actor   string    `json:"actor"`
+	Created time.Time `json:"created"`
+	Read    bool      `json:"read"`
+}
+
+//easyjson:json
+type Notifications struct {
+	Unread        int            `json:"unread"`
+	Notifications []Notification `json:"notifications"`
+}
+
+//easyjson:json
+type UnreadCount struct {
+	Unread int `json:"unread"`
+}
+
+type UserGetNotifications struct {
+	Nickname string
+	Limit    int
+	Since    int
+	Unread   bool
+}
+
+//easyjson:json
+type NotificationsRead struct {
+	Nickname string `json:"-"`
+	IDs      []int  `json:"ids"`
+	All      bool   `json:"all"`
+}
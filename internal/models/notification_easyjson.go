@@ -0,0 +1,490 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels(in *jlexer.Lexer, out *UnreadCount) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "unread":
+			out.Unread = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels(out *jwriter.Writer, in UnreadCount) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"unread\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Unread))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UnreadCount) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UnreadCount) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UnreadCount) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UnreadCount) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels(l, v)
+}
+func easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels1(in *jlexer.Lexer, out *Subscription) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "nickname":
+			out.User = string(in.String())
+		case "thread":
+			out.Thread = int(in.Int())
+		case "forum":
+			out.Forum = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels1(out *jwriter.Writer, in Subscription) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.User))
+	}
+	if in.Thread != 0 {
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int(int(in.Thread))
+	}
+	if in.Forum != "" {
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix)
+		out.String(string(in.Forum))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Subscription) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Subscription) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Subscription) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Subscription) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels1(l, v)
+}
+func easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels2(in *jlexer.Lexer, out *NotificationsRead) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "ids":
+			if in.IsNull() {
+				in.Skip()
+				out.IDs = nil
+			} else {
+				in.Delim('[')
+				if out.IDs == nil {
+					if !in.IsDelim(']') {
+						out.IDs = make([]int, 0, 8)
+					} else {
+						out.IDs = []int{}
+					}
+				} else {
+					out.IDs = (out.IDs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 int
+					v1 = int(in.Int())
+					out.IDs = append(out.IDs, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "all":
+			out.All = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels2(out *jwriter.Writer, in NotificationsRead) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"ids\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.IDs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.IDs {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.Int(int(v3))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"all\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.All))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NotificationsRead) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels2(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NotificationsRead) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels2(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NotificationsRead) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels2(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NotificationsRead) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels2(l, v)
+}
+func easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels3(in *jlexer.Lexer, out *Notifications) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "unread":
+			out.Unread = int(in.Int())
+		case "notifications":
+			if in.IsNull() {
+				in.Skip()
+				out.Notifications = nil
+			} else {
+				in.Delim('[')
+				if out.Notifications == nil {
+					if !in.IsDelim(']') {
+						out.Notifications = make([]Notification, 0, 1)
+					} else {
+						out.Notifications = []Notification{}
+					}
+				} else {
+					out.Notifications = (out.Notifications)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 Notification
+					(v4).UnmarshalEasyJSON(in)
+					out.Notifications = append(out.Notifications, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels3(out *jwriter.Writer, in Notifications) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"unread\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Unread))
+	}
+	{
+		const prefix string = ",\"notifications\":"
+		out.RawString(prefix)
+		if in.Notifications == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.Notifications {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				(v6).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Notifications) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Notifications) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Notifications) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Notifications) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels3(l, v)
+}
+func easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels4(in *jlexer.Lexer, out *Notification) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int(in.Int())
+		case "kind":
+			out.Kind = string(in.String())
+		case "thread":
+			out.Thread = int(in.Int())
+		case "post":
+			out.Post = int(in.Int())
+		case "actor":
+			out.Actor = string(in.String())
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
+			}
+		case "read":
+			out.Read = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels4(out *jwriter.Writer, in Notification) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.ID))
+	}
+	{
+		const prefix string = ",\"kind\":"
+		out.RawString(prefix)
+		out.String(string(in.Kind))
+	}
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int(int(in.Thread))
+	}
+	if in.Post != 0 {
+		const prefix string = ",\"post\":"
+		out.RawString(prefix)
+		out.Int(int(in.Post))
+	}
+	{
+		const prefix string = ",\"actor\":"
+		out.RawString(prefix)
+		out.String(string(in.Actor))
+	}
+	{
+		const prefix string = ",\"created\":"
+		out.RawString(prefix)
+		out.Raw((in.Created).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"read\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Read))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Notification) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Notification) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson9806e1EncodeGithubComEgorAistTPDBProjectInternalModels4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Notification) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Notification) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson9806e1DecodeGithubComEgorAistTPDBProjectInternalModels4(l, v)
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+//easyjson:json
+type ReadMarker struct {
+	User   string `json:"nickname"`
+	Thread int    `json:"thread"`
+	Post   int    `json:"post,omitempty"` // Последнее прочитанное сообщение; без него прочитана вся ветка.
+	Unread int    `json:"unread"`
+}
+
+//easyjson:json
+type UserReadMarker struct {
+	Thread  int       `json:"thread"`
+	Post    int       `json:"post"`
+	Updated time.Time `json:"updated"`
+}
@@ -0,0 +1,188 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package models
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjsonE7639c6cDecodeGithubComEgorAistTPDBProjectInternalModels(in *jlexer.Lexer, out *UserReadMarker) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "thread":
+			out.Thread = int(in.Int())
+		case "post":
+			out.Post = int(in.Int())
+		case "updated":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Updated).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE7639c6cEncodeGithubComEgorAistTPDBProjectInternalModels(out *jwriter.Writer, in UserReadMarker) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Thread))
+	}
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix)
+		out.Int(int(in.Post))
+	}
+	{
+		const prefix string = ",\"updated\":"
+		out.RawString(prefix)
+		out.Raw((in.Updated).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserReadMarker) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE7639c6cEncodeGithubComEgorAistTPDBProjectInternalModels(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserReadMarker) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE7639c6cEncodeGithubComEgorAistTPDBProjectInternalModels(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserReadMarker) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE7639c6cDecodeGithubComEgorAistTPDBProjectInternalModels(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserReadMarker) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE7639c6cDecodeGithubComEgorAistTPDBProjectInternalModels(l, v)
+}
+func easyjsonE7639c6cDecodeGithubComEgorAistTPDBProjectInternalModels1(in *jlexer.Lexer, out *ReadMarker) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeString()
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "nickname":
+			out.User = string(in.String())
+		case "thread":
+			out.Thread = int(in.Int())
+		case "post":
+			out.Post = int(in.Int())
+		case "unread":
+			out.Unread = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonE7639c6cEncodeGithubComEgorAistTPDBProjectInternalModels1(out *jwriter.Writer, in ReadMarker) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.User))
+	}
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int(int(in.Thread))
+	}
+	if in.Post != 0 {
+		const prefix string = ",\"post\":"
+		out.RawString(prefix)
+		out.Int(int(in.Post))
+	}
+	{
+		const prefix string = ",\"unread\":"
+		out.RawString(prefix)
+		out.Int(int(in.Unread))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ReadMarker) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonE7639c6cEncodeGithubComEgorAistTPDBProjectInternalModels1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReadMarker) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonE7639c6cEncodeGithubComEgorAistTPDBProjectInternalModels1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ReadMarker) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonE7639c6cDecodeGithubComEgorAistTPDBProjectInternalModels1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReadMarker) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonE7639c6cDecodeGithubComEgorAistTPDBProjectInternalModels1(l, v)
+}
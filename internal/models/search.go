@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of search hits.
+const (
+	SearchPost   = "post"
+	SearchThread = "thread"
+)
+
+// Page sizes of a search: the one used when none is asked for and the largest.
+const (
+	SearchDefaultLimit = 20
+	MaxSearchLimit     = 100
+)
+
+type SearchInput struct {
+	Query  string
+	Forum  string
+	Thread int
+	Author string
+	Since  string
+	Until  string
+	Kind   string // SearchPost or SearchThread; both when empty.
+	Limit  int
+	After  *SearchCursor
+}
+
+// SearchCursor is the position of the last hit of a page: hits are ordered by
+// rank, then kind and ID.
+type SearchCursor struct {
+	Rank float32
+	Kind string
+	ID   int
+}
+
+var errBadCursor = errors.New("malformed cursor")
+
+func (c SearchCursor) String() string {
+	raw := strconv.FormatFloat(float64(c.Rank), 'g', -1, 32) + ":" + c.Kind + ":" + strconv.Itoa(c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseSearchCursor reads a cursor made by SearchCursor.String.
+func ParseSearchCursor(value string) (SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return SearchCursor{}, errBadCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 || (parts[1] != SearchPost && parts[1] != SearchThread) {
+		return SearchCursor{}, errBadCursor
+	}
+	rank, err := strconv.ParseFloat(parts[0], 32)
+	if err != nil {
+		return SearchCursor{}, errBadCursor
+	}
+	id, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return SearchCursor{}, errBadCursor
+	}
+
+	return SearchCursor{Rank: float32(rank), Kind: parts[1], ID: id}, nil
+}
+
+//easyjson:json
+type SearchHit struct {
+	Kind    string    `json:"kind"`
+	ID      int       `json:"id"`
+	Thread  int       `json:"thread"`
+	Forum   string    `json:"forum"`
+	Author  string    `json:"author"`
+	Created time.Time `json:"created"`
+	Title   string    `json:"title,omitempty"`
+	Snippet string    `json:"snippet"` // Matches are wrapped in <mark></mark>.
+	Rank    float32   `json:"rank"`
+}
+
+//easyjson:json
+type SearchResult struct {
+	Hits []SearchHit `json:"hits"`
+	Next string      `json:"next,omitempty"` // Cursor of the next page, if any.
+}
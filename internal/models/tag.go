@@ -0,0 +1,19 @@
+package models
+
+//easyjson:json
+type Tag struct {
+	Name    string `json:"tag"`
+	Threads int    `json:"threads"` // Число веток с этой меткой.
+}
+
+type TagGetThreads struct {
+	Tag   string
+	Limit int
+	Since string
+	Desc  bool
+}
+
+type TagsGet struct {
+	Prefix string
+	Limit  int
+}
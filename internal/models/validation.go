@@ -0,0 +1,291 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Length limits of user supplied text, in characters.
+const (
+	MaxNameLength     = 256
+	MaxAboutLength    = 4096
+	MaxMessageLength  = 65536
+	MaxReactionLength = 32
+	MaxTagLength      = 32
+)
+
+// MaxTags is how many tags a thread may carry.
+const MaxTags = 10
+
+var (
+	slugPattern     = regexp.MustCompile(`^[\w-]*[A-Za-z_-][\w-]*$`)
+	nicknamePattern = regexp.MustCompile(`^[\w.]+$`)
+	emailPattern    = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	tagPattern      = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+)
+
+//easyjson:json
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// NewValidation returns a Validation error listing every offending field.
+func NewValidation(fields ...FieldError) Error {
+	return Error{Kind: KindValidation, Reason: ReasonInvalidInput, Message: "invalid request", Fields: fields}
+}
+
+// NewMalformed returns a Validation error for a body that is not valid JSON.
+func NewMalformed(err error) Error {
+	return Error{Kind: KindValidation, Reason: ReasonMalformedBody, Message: "malformed request body", Err: err}
+}
+
+// Validator collects field errors so a request reports all of them at once.
+type Validator struct {
+	fields []FieldError
+}
+
+func (v *Validator) Add(field string, message string) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: message})
+}
+
+// Check adds an error for field unless ok holds.
+func (v *Validator) Check(ok bool, field string, message string) {
+	if !ok {
+		v.Add(field, message)
+	}
+}
+
+func (v *Validator) Required(field string, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, "is required")
+		return false
+	}
+	return true
+}
+
+func (v *Validator) MaxLength(field string, value string, max int) {
+	v.Check(utf8.RuneCountInString(value) <= max, field, fmt.Sprintf("must be at most %d characters", max))
+}
+
+func (v *Validator) Slug(field string, value string) {
+	v.Check(slugPattern.MatchString(value), field, "must contain only letters, digits, '-' and '_' and not be a number")
+}
+
+func (v *Validator) Nickname(field string, value string) {
+	v.Check(nicknamePattern.MatchString(value), field, "must contain only letters, digits, '_' and '.'")
+}
+
+func (v *Validator) Tags(field string, tags []string) {
+	v.Check(len(tags) <= MaxTags, field, fmt.Sprintf("must have at most %d tags", MaxTags))
+	for i, tag := range tags {
+		name := fmt.Sprintf("%s[%d]", field, i)
+		v.Check(tagPattern.MatchString(tag), name, "must contain only letters, digits, '-' and '_'")
+		v.MaxLength(name, tag, MaxTagLength)
+	}
+}
+
+func (v *Validator) Email(field string, value string) {
+	v.Check(emailPattern.MatchString(value), field, "must be a valid email address")
+}
+
+// Err returns nil if nothing was reported.
+func (v *Validator) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return NewValidation(v.fields...)
+}
+
+func (f ForumCreate) Validate() error {
+	v := Validator{}
+	if v.Required("slug", f.Slug) {
+		v.Slug("slug", f.Slug)
+	}
+	if v.Required("title", f.Title) {
+		v.MaxLength("title", f.Title, MaxNameLength)
+	}
+	if v.Required("user", f.User) {
+		v.Nickname("user", f.User)
+	}
+	if f.Parent != "" {
+		v.Slug("parent", f.Parent)
+	}
+	return v.Err()
+}
+
+func (u UserRename) Validate() error {
+	v := Validator{}
+	if v.Required("nickname", u.NewNickname) {
+		v.Nickname("nickname", u.NewNickname)
+	}
+	return v.Err()
+}
+
+// Validate checks a forum update, where every field is optional.
+func (f ForumUpdate) Validate() error {
+	v := Validator{}
+	if f.NewSlug != "" {
+		v.Slug("slug", f.NewSlug)
+	}
+	v.MaxLength("title", f.Title, MaxNameLength)
+	if f.User != "" {
+		v.Nickname("user", f.User)
+	}
+	if f.Parent != nil && *f.Parent != "" {
+		v.Slug("parent", *f.Parent)
+	}
+	return v.Err()
+}
+
+// Validate checks a user being created: every field but about is required.
+func (u User) Validate() error {
+	v := Validator{}
+	if v.Required("nickname", u.Nickname) {
+		v.Nickname("nickname", u.Nickname)
+	}
+	if v.Required("fullname", u.Fullname) {
+		v.MaxLength("fullname", u.Fullname, MaxNameLength)
+	}
+	if v.Required("email", u.Email) {
+		v.Email("email", u.Email)
+	}
+	v.MaxLength("about", u.About, MaxAboutLength)
+	return v.Err()
+}
+
+// ValidateUpdate checks a profile update, where every field is optional.
+func (u User) ValidateUpdate() error {
+	v := Validator{}
+	v.Nickname("nickname", u.Nickname)
+	v.MaxLength("fullname", u.Fullname, MaxNameLength)
+	if u.Email != "" {
+		v.Email("email", u.Email)
+	}
+	v.MaxLength("about", u.About, MaxAboutLength)
+	return v.Err()
+}
+
+func (t Thread) Validate() error {
+	v := Validator{}
+	if v.Required("author", t.Author) {
+		v.Nickname("author", t.Author)
+	}
+	if v.Required("forum", t.Forum) {
+		v.Slug("forum", t.Forum)
+	}
+	if v.Required("title", t.Title) {
+		v.MaxLength("title", t.Title, MaxNameLength)
+	}
+	if v.Required("message", t.Message) {
+		v.MaxLength("message", t.Message, MaxMessageLength)
+	}
+	if t.Slug != "" {
+		v.Slug("slug", t.Slug)
+	}
+	v.Tags("tags", t.Tags)
+	return v.Err()
+}
+
+func (t ThreadUpdate) Validate() error {
+	v := Validator{}
+	v.MaxLength("title", t.Title, MaxNameLength)
+	v.MaxLength("message", t.Message, MaxMessageLength)
+	if t.Editor != "" {
+		v.Nickname("editor", t.Editor)
+	}
+	v.Tags("tags", t.Tags)
+	return v.Err()
+}
+
+// Validate checks a state change; threads are deleted through their own
+// endpoint, not by setting the state.
+func (t ThreadState) Validate() error {
+	v := Validator{}
+	v.Check(t.State == ThreadOpen || t.State == ThreadLocked || t.State == ThreadArchived,
+		"state", "must be one of open, locked, archived")
+	return v.Err()
+}
+
+func (vote Vote) Validate() error {
+	v := Validator{}
+	if v.Required("nickname", vote.User) {
+		v.Nickname("nickname", vote.User)
+	}
+	v.Check(vote.Voice == 1 || vote.Voice == -1, "voice", "must be 1 or -1")
+	return v.Err()
+}
+
+func (vote PostVote) Validate() error {
+	v := Validator{}
+	if v.Required("nickname", vote.User) {
+		v.Nickname("nickname", vote.User)
+	}
+	v.Check(vote.Voice == 1 || vote.Voice == -1, "voice", "must be 1 or -1")
+	return v.Err()
+}
+
+// Validate checks a reaction: any short text without spaces, such as an emoji
+// or a name like "thumbs_up".
+func (r PostReaction) Validate() error {
+	v := Validator{}
+	if v.Required("nickname", r.User) {
+		v.Nickname("nickname", r.User)
+	}
+	if v.Required("reaction", r.Reaction) {
+		v.MaxLength("reaction", r.Reaction, MaxReactionLength)
+		v.Check(!strings.ContainsAny(r.Reaction, " \t\r\n"), "reaction", "must not contain spaces")
+	}
+	return v.Err()
+}
+
+// ValidatePosts checks a batch of new posts; fields are reported as
+// posts[i].field.
+func ValidatePosts(posts []PostCreate) error {
+	v := Validator{}
+	for i, post := range posts {
+		prefix := fmt.Sprintf("posts[%d].", i)
+		if v.Required(prefix+"author", post.Author) {
+			v.Nickname(prefix+"author", post.Author)
+		}
+		if v.Required(prefix+"message", post.Message) {
+			v.MaxLength(prefix+"message", post.Message, MaxMessageLength)
+		}
+		v.Check(post.Parent >= 0, prefix+"parent", "must not be negative")
+	}
+	return v.Err()
+}
+
+func (p PostUpdate) Validate() error {
+	v := Validator{}
+	v.MaxLength("message", p.Message, MaxMessageLength)
+	if p.Editor != "" {
+		v.Nickname("editor", p.Editor)
+	}
+	return v.Err()
+}
+
+func (s Subscription) Validate() error {
+	v := Validator{}
+	if v.Required("nickname", s.User) {
+		v.Nickname("nickname", s.User)
+	}
+	return v.Err()
+}
+
+func (m ReadMarker) Validate() error {
+	v := Validator{}
+	if v.Required("nickname", m.User) {
+		v.Nickname("nickname", m.User)
+	}
+	v.Check(m.Post >= 0, "post", "must not be negative")
+	return v.Err()
+}
+
+func (r NotificationsRead) Validate() error {
+	v := Validator{}
+	v.Check(r.All || len(r.IDs) > 0, "ids", "must not be empty unless all is set")
+	return v.Err()
+}
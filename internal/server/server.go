@@ -0,0 +1,139 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+var ErrShutdownTimeout = errors.New("shutdown deadline exceeded with requests in flight")
+
+// Request describes a request that was still being handled when the
+// shutdown deadline passed.
+type Request struct {
+	Method  string
+	Path    string
+	Started time.Time
+}
+
+// Server wraps fasthttp.Server and keeps track of in-flight requests so that
+// Shutdown can drain them and report the ones it had to give up on.
+type Server struct {
+	srv *fasthttp.Server
+
+	stopping int32
+	mu       sync.Mutex
+	inFlight map[uint64]Request
+	idle     chan struct{}
+}
+
+func New(srv *fasthttp.Server) *Server {
+	s := &Server{
+		srv:      srv,
+		inFlight: make(map[uint64]Request),
+	}
+	srv.Handler = s.track(srv.Handler)
+	return s
+}
+
+func (s *Server) ListenAndServe(addr string) error {
+	return s.srv.ListenAndServe(addr)
+}
+
+func (s *Server) Serve(ln net.Listener) error {
+	return s.srv.Serve(ln)
+}
+
+func (s *Server) track(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(c *fasthttp.RequestCtx) {
+		id := c.ID()
+
+		s.mu.Lock()
+		s.inFlight[id] = Request{
+			Method:  string(c.Method()),
+			Path:    string(c.Path()),
+			Started: time.Now(),
+		}
+		s.mu.Unlock()
+
+		defer func() {
+			s.mu.Lock()
+			delete(s.inFlight, id)
+			if len(s.inFlight) == 0 && s.idle != nil {
+				close(s.idle)
+				s.idle = nil
+			}
+			s.mu.Unlock()
+		}()
+
+		if atomic.LoadInt32(&s.stopping) == 1 {
+			c.SetConnectionClose()
+		}
+
+		next(c)
+	}
+}
+
+// Shutdown stops accepting new connections and waits up to timeout for the
+// requests in flight to finish and for the connections to close, so that
+// nothing is served once it returns. When the deadline passes, the requests
+// still running are returned together with ErrShutdownTimeout; they are
+// aborted once the process exits.
+func (s *Server) Shutdown(timeout time.Duration) ([]Request, error) {
+	atomic.StoreInt32(&s.stopping, 1)
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- s.srv.Shutdown()
+	}()
+
+	s.mu.Lock()
+	idle := make(chan struct{})
+	if len(s.inFlight) == 0 {
+		close(idle)
+	} else {
+		s.idle = idle
+	}
+	s.mu.Unlock()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	select {
+	case err := <-closed:
+		return nil, err
+	case <-idle:
+	case <-deadline.C:
+		return s.aborted()
+	}
+
+	// The requests are done, but a keep-alive connection may still bring in
+	// another one until fasthttp has closed it.
+	select {
+	case err := <-closed:
+		return nil, err
+	case <-deadline.C:
+		return s.aborted()
+	}
+}
+
+// aborted lists the requests still in flight after the deadline.
+func (s *Server) aborted() ([]Request, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	aborted := make([]Request, 0, len(s.inFlight))
+	for _, request := range s.inFlight {
+		aborted = append(aborted, request)
+	}
+
+	if len(aborted) == 0 {
+		return nil, nil
+	}
+
+	return aborted, ErrShutdownTimeout
+}
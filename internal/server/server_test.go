@@ -0,0 +1,88 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestShutdownDrainsInFlightRequest(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	srv := New(&fasthttp.Server{
+		Handler: func(c *fasthttp.RequestCtx) {
+			close(entered)
+			<-release
+			c.SetStatusCode(fasthttp.StatusOK)
+		},
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(ln)
+
+	statuses := make(chan int, 1)
+	errs := make(chan error, 1)
+	go func() {
+		client := http.Client{Timeout: 5 * time.Second}
+		resp, err := client.Get("http://" + ln.Addr().String() + "/")
+		if err != nil {
+			errs <- err
+			return
+		}
+		resp.Body.Close()
+		statuses <- resp.StatusCode
+	}()
+
+	select {
+	case <-entered:
+	case err := <-errs:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("request did not reach the handler")
+	}
+
+	type result struct {
+		aborted []Request
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		aborted, err := srv.Shutdown(5 * time.Second)
+		done <- result{aborted, err}
+	}()
+
+	// Shutdown must wait for the parked request rather than return at once.
+	select {
+	case r := <-done:
+		t.Fatalf("Shutdown returned before the request finished: %v %v", r.aborted, r.err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case status := <-statuses:
+		if status != http.StatusOK {
+			t.Fatalf("status = %d, want %d", status, http.StatusOK)
+		}
+	case err := <-errs:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no response to the in-flight request")
+	}
+
+	select {
+	case r := <-done:
+		if r.err != nil || len(r.aborted) != 0 {
+			t.Fatalf("Shutdown() = %v, %v, want no aborted requests and nil", r.aborted, r.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return")
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"strings"
+)
+
+// maxDiffCells bounds the LCS table of diffLines. Beyond it the changed lines
+// are reported as removed and added as a whole.
+const maxDiffCells = 4 << 20
+
+// diffLines returns the line-level changes that turn from into to.
+func diffLines(from, to string) []models.DiffLine {
+	a := strings.Split(from, "\n")
+	b := strings.Split(to, "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	diff := make([]models.DiffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		diff = append(diff, models.DiffLine{Op: models.DiffEqual, Text: line})
+	}
+	diff = append(diff, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		diff = append(diff, models.DiffLine{Op: models.DiffEqual, Text: line})
+	}
+
+	return diff
+}
+
+// diffMiddle diffs the lines between the common prefix and suffix by their
+// longest common subsequence.
+func diffMiddle(a, b []string) []models.DiffLine {
+	diff := make([]models.DiffLine, 0, len(a)+len(b))
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			diff = append(diff, models.DiffLine{Op: models.DiffDelete, Text: line})
+		}
+		for _, line := range b {
+			diff = append(diff, models.DiffLine{Op: models.DiffInsert, Text: line})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:].
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, models.DiffLine{Op: models.DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, models.DiffLine{Op: models.DiffDelete, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, models.DiffLine{Op: models.DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, models.DiffLine{Op: models.DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, models.DiffLine{Op: models.DiffInsert, Text: b[j]})
+	}
+
+	return diff
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"regexp"
+	"strings"
+)
+
+// mentionPattern finds @nickname not glued to a word before it, so e-mail
+// addresses are not taken for mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w.@])@([\w.]+)`)
+
+// parseMentions returns the nicknames mentioned in the message, each once,
+// spelled as first mentioned. A dot ending a sentence is not part of the
+// nickname.
+func parseMentions(message string) []string {
+	var nicknames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(message, -1) {
+		nickname := strings.TrimRight(match[1], ".")
+		key := strings.ToLower(nickname)
+		if nickname == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		nicknames = append(nicknames, nickname)
+	}
+	return nicknames
+}
+
+// checkMentions parses the mentions of the message and looks the users up.
+// It returns the nicknames of the users found and the mentioned nicknames no
+// user has. With Options.RejectUnknownMentions the latter are a validation
+// error of field.
+func (s service) checkMentions(ctx context.Context, field string, message string) (found []string, unknown []string, err error) {
+	nicknames := parseMentions(message)
+	if len(nicknames) == 0 {
+		return nil, nil, nil
+	}
+
+	found, err = s.mentionStorage.ResolveNicknames(ctx, nicknames)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exists := make(map[string]bool, len(found))
+	for _, nickname := range found {
+		exists[strings.ToLower(nickname)] = true
+	}
+	for _, nickname := range nicknames {
+		if !exists[strings.ToLower(nickname)] {
+			unknown = append(unknown, nickname)
+		}
+	}
+
+	if len(unknown) > 0 && s.options.RejectUnknownMentions {
+		return nil, nil, models.NewValidation(models.FieldError{
+			Field:   field,
+			Message: fmt.Sprintf("mentions unknown users: %s", strings.Join(unknown, ", ")),
+		})
+	}
+	return found, unknown, nil
+}
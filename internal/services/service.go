@@ -1,39 +1,81 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/EgorAist/TP_DB_project/internal/logger"
 	"github.com/EgorAist/TP_DB_project/internal/models"
 	"github.com/EgorAist/TP_DB_project/internal/storages/databaseService"
 	"github.com/EgorAist/TP_DB_project/internal/storages/forumStorage"
+	"github.com/EgorAist/TP_DB_project/internal/storages/mentionStorage"
+	"github.com/EgorAist/TP_DB_project/internal/storages/notificationStorage"
 	"github.com/EgorAist/TP_DB_project/internal/storages/postStorage"
+	"github.com/EgorAist/TP_DB_project/internal/storages/readMarkerStorage"
+	"github.com/EgorAist/TP_DB_project/internal/storages/searchStorage"
 	"github.com/EgorAist/TP_DB_project/internal/storages/threadStorage"
 	"github.com/EgorAist/TP_DB_project/internal/storages/userStorage"
 	"github.com/EgorAist/TP_DB_project/internal/storages/voteStorage"
+	"github.com/jackc/pgx"
 	"math"
 	"strings"
+	"time"
 )
 
 type Service interface {
-	CreateForum(input models.ForumCreate) (models.Forum, error)
-	GetForum(input models.ForumInput) (models.Forum, error)
-	GetForumThreads(input models.ForumGetThreads) ([]models.Thread, error)
-	GetForumUsers(input models.ForumGetUsers) ([]models.User, error)
+	CreateForum(ctx context.Context, input models.ForumCreate) (models.Forum, error)
+	GetForum(ctx context.Context, input models.ForumInput) (models.Forum, error)
+	GetForumThreads(ctx context.Context, input models.ForumGetThreads) ([]models.Thread, error)
+	GetForumChildren(ctx context.Context, input models.ForumInput) ([]models.Forum, error)
+	GetForumUsers(ctx context.Context, input models.ForumGetUsers) ([]models.User, error)
+	UpdateForum(ctx context.Context, input models.ForumUpdate) (models.Forum, error)
+	DeleteForum(ctx context.Context, input models.ForumInput, dryRun bool) (models.ForumDeletion, error)
 
-	CreateUser(input models.User) ([]models.User, error)
-	GetUser(nickname string) (models.User, error)
-	UpdateUser(input models.User) (models.User, error)
+	CreateUser(ctx context.Context, input models.User) ([]models.User, error)
+	GetUser(ctx context.Context, nickname string) (models.User, error)
+	UpdateUser(ctx context.Context, input models.User) (models.User, error)
+	RenameUser(ctx context.Context, input models.UserRename) (models.User, error)
+	ResolveNickname(ctx context.Context, alias string) (string, error)
+	DeleteUser(ctx context.Context, nickname string, mode string) (models.UserDeletion, error)
+	ExportUser(ctx context.Context, nickname string) (models.UserExport, error)
+	GetUserThreads(ctx context.Context, input models.UserGetThreads) ([]models.Thread, error)
+	GetUserPosts(ctx context.Context, input models.UserGetPosts) ([]models.Post, error)
+	GetUserMentions(ctx context.Context, input models.UserGetMentions) ([]models.Mention, error)
 
-	CreateThread(input models.Thread) (models.Thread, error)
-	ThreadVote(input models.Vote) (models.Thread, error)
-	GetThread(input models.ThreadInput) (models.Thread, error)
-	UpdateThread(input models.ThreadUpdate) (models.Thread, error)
-	GetThreadPosts(input models.ThreadGetPosts) ([]models.Post, error)
+	CreateThread(ctx context.Context, input models.Thread) (models.Thread, error)
+	ThreadVote(ctx context.Context, input models.Vote) (models.Thread, error)
+	GetThread(ctx context.Context, input models.ThreadInput, viewer string) (models.Thread, error)
+	MarkThreadRead(ctx context.Context, thread models.ThreadInput, input models.ReadMarker) (models.ReadMarker, error)
+	UpdateThread(ctx context.Context, input models.ThreadUpdate) (models.Thread, error)
+	GetThreadHistory(ctx context.Context, input models.ThreadGetHistory) ([]models.ThreadRevision, error)
+	SetThreadState(ctx context.Context, input models.ThreadState) (models.Thread, error)
+	DeleteThread(ctx context.Context, input models.ThreadInput) (models.Thread, error)
+	GetThreadPosts(ctx context.Context, input models.ThreadGetPosts) ([]models.Post, error)
+	GetTagThreads(ctx context.Context, input models.TagGetThreads) ([]models.Thread, error)
+	GetTags(ctx context.Context, input models.TagsGet) ([]models.Tag, error)
 
-	GetPost(id int, related string) (models.PostFull, error)
-	UpdatePost(input models.PostUpdate) (models.Post, error)
+	CreatePosts(ctx context.Context, thread models.ThreadInput, posts []models.PostCreate) ([]models.Post, error)
+	GetPost(ctx context.Context, id int, related string) (models.PostFull, error)
+	UpdatePost(ctx context.Context, input models.PostUpdate) (models.Post, error)
+	GetPostHistory(ctx context.Context, input models.PostGetHistory) ([]models.PostRevision, error)
+	DeletePost(ctx context.Context, id int) (models.Post, error)
+	PurgePostSubtree(ctx context.Context, id int) (models.PostsPurged, error)
+	PostVote(ctx context.Context, input models.PostVote) (models.Post, error)
+	AddPostReaction(ctx context.Context, input models.PostReaction) (models.Post, error)
+	RemovePostReaction(ctx context.Context, input models.PostReaction) (models.Post, error)
 
-	Clear()
-	Status() models.Status
+	Search(ctx context.Context, input models.SearchInput) (models.SearchResult, error)
+
+	SubscribeThread(ctx context.Context, thread models.ThreadInput, input models.Subscription) (models.Subscription, error)
+	UnsubscribeThread(ctx context.Context, thread models.ThreadInput, input models.Subscription) (models.Subscription, error)
+	SubscribeForum(ctx context.Context, input models.Subscription) (models.Subscription, error)
+	UnsubscribeForum(ctx context.Context, input models.Subscription) (models.Subscription, error)
+	GetNotifications(ctx context.Context, input models.UserGetNotifications) (models.Notifications, error)
+	MarkNotificationsRead(ctx context.Context, input models.NotificationsRead) (models.UnreadCount, error)
+
+	Clear(ctx context.Context)
+	Status(ctx context.Context) models.Status
+	SlowQueries(ctx context.Context) []models.SlowQuery
 }
 
 type service struct {
@@ -42,29 +84,75 @@ type service struct {
 	userStorage userStorage.Storage
 	postStorage postStorage.Storage
 	voteStorage voteStorage.Storage
+	searchStorage searchStorage.Storage
+	notificationStorage notificationStorage.Storage
+	mentionStorage mentionStorage.Storage
+	readMarkerStorage readMarkerStorage.Storage
 	databaseService databaseService.Service
+	unitOfWork UnitOfWork
+	options Options
+	log *logger.Logger
+}
+
+// Options holds the settings of the service that come from the configuration.
+type Options struct {
+	// NicknameAliasTTL is how long the old nickname of a renamed user stays
+	// reserved.
+	NicknameAliasTTL time.Duration
+	// RejectUnknownMentions refuses messages that mention nicknames no user
+	// has instead of listing them in the response.
+	RejectUnknownMentions bool
 }
 
-func NewService(forumStorage forumStorage.Storage, threadStorage threadStorage.Storage, userStorage userStorage.Storage, postStorage postStorage.Storage, voteStorage voteStorage.Storage, databaseService databaseService.Service) Service {
+func NewService(forumStorage forumStorage.Storage, threadStorage threadStorage.Storage, userStorage userStorage.Storage, postStorage postStorage.Storage, voteStorage voteStorage.Storage, searchStorage searchStorage.Storage, notificationStorage notificationStorage.Storage, mentionStorage mentionStorage.Storage, readMarkerStorage readMarkerStorage.Storage, databaseService databaseService.Service, unitOfWork UnitOfWork, options Options, log *logger.Logger) Service {
 	return &service{
 		forumStorage:  forumStorage,
 		threadStorage: threadStorage,
 		userStorage:   userStorage,
 		postStorage:   postStorage,
 		voteStorage:   voteStorage,
+		searchStorage: searchStorage,
+		notificationStorage: notificationStorage,
+		mentionStorage: mentionStorage,
+		readMarkerStorage: readMarkerStorage,
 		databaseService: databaseService,
+		unitOfWork: unitOfWork,
+		options: options,
+		log: log,
 	}
 }
 
-func (s service) CreateForum(input models.ForumCreate) (models.Forum, error) {
-	forum, err := s.forumStorage.CreateForum(input)
-	if err != nil && err.Error() == "409" {
-		oldForum, err := s.forumStorage.GetDetails(models.ForumInput{Slug: input.Slug})
+func (s service) CreateForum(ctx context.Context, input models.ForumCreate) (models.Forum, error) {
+	if input.Parent != "" {
+		parent, err := s.getForum(ctx, models.ForumInput{Slug: input.Parent})
+		if err != nil {
+			return models.Forum{}, err
+		}
+		input.Parent = parent.Slug
+	}
+
+	// The old slug of a renamed forum still leads to it, so it cannot be
+	// taken by a new one.
+	owner, err := s.forumStorage.ResolveAlias(ctx, input.Slug)
+	if err == nil {
+		ownerForum, err := s.forumStorage.GetDetails(ctx, models.ForumInput{Slug: owner})
 		if err != nil {
 			return models.Forum{}, err
 		}
+		return ownerForum, models.NewConflict(models.EntityForum, models.ReasonAlreadyExists, "slug is an alias of another forum")
+	}
+	if !errors.Is(err, models.ErrNotFound) {
+		return models.Forum{}, err
+	}
+
+	forum, err := s.forumStorage.CreateForum(ctx, input)
+	if errors.Is(err, models.ErrConflict) {
+		oldForum, errOld := s.forumStorage.GetDetails(ctx, models.ForumInput{Slug: input.Slug})
+		if errOld != nil {
+			return models.Forum{}, errOld
+		}
 
-		return oldForum, models.Error{Code: "409", Message: "conflict slug"}
+		return oldForum, err
 	}
 
 	if err != nil {
@@ -74,45 +162,264 @@ func (s service) CreateForum(input models.ForumCreate) (models.Forum, error) {
 	return forum, nil
 }
 
-func (s service) GetForum(input models.ForumInput) (models.Forum, error) {
-	return s.forumStorage.GetDetails(input)
+// GetForum returns the forum with the counters of its whole subtree.
+func (s service) GetForum(ctx context.Context, input models.ForumInput) (models.Forum, error) {
+	forum, err := s.getForum(ctx, input)
+	if err != nil {
+		return models.Forum{}, err
+	}
+
+	totals, err := s.forumStorage.GetTotals(ctx, models.ForumInput{Slug: forum.Slug})
+	if err != nil {
+		return models.Forum{}, err
+	}
+	forum.Totals = &totals
+
+	return forum, nil
 }
 
-func (s service) GetForumThreads(input models.ForumGetThreads) ([]models.Thread, error) {
-	err := s.forumStorage.CheckIfForumExists(models.ForumInput{Slug: input.Slug})
+func (s service) getForum(ctx context.Context, input models.ForumInput) (models.Forum, error) {
+	forum, err := s.forumStorage.GetDetails(ctx, input)
 	if err != nil {
-		return []models.Thread{}, err
+		if input.Slug, err = s.forumAlias(ctx, input.Slug, err); err != nil {
+			return models.Forum{}, err
+		}
+		return s.forumStorage.GetDetails(ctx, input)
+	}
+
+	return forum, nil
+}
+
+// GetForumChildren lists the direct subforums of the forum.
+func (s service) GetForumChildren(ctx context.Context, input models.ForumInput) ([]models.Forum, error) {
+	forum, err := s.getForum(ctx, input)
+	if err != nil {
+		return []models.Forum{}, err
+	}
+	return s.forumStorage.GetChildren(ctx, models.ForumInput{Slug: forum.Slug})
+}
+
+func (s service) GetForumThreads(ctx context.Context, input models.ForumGetThreads) ([]models.Thread, error) {
+	err := s.forumStorage.CheckIfForumExists(ctx, models.ForumInput{Slug: input.Slug})
+	if err != nil {
+		if input.Slug, err = s.forumAlias(ctx, input.Slug, err); err != nil {
+			return []models.Thread{}, err
+		}
 	}
 	if input.Limit == 0 {
 		input.Limit = math.MaxInt32
 	}
-	return s.threadStorage.GetThreadsByForum(input)
+	input.Tags = normalizeTags(input.Tags)
+	var threads []models.Thread
+	if input.Descendants {
+		threads, err = s.getSubtreeThreads(ctx, input)
+	} else {
+		threads, err = s.threadStorage.GetThreadsByForum(ctx, input)
+	}
+	if err != nil || input.Viewer == "" {
+		return threads, err
+	}
+
+	if err = s.withUnread(ctx, input.Viewer, threads); err != nil {
+		return []models.Thread{}, err
+	}
+	return threads, nil
 }
 
-func (s service) GetForumUsers(input models.ForumGetUsers) ([]models.User, error) {
-	_, err := s.forumStorage.GetForumID(models.ForumInput{Slug: input.Slug})
+func (s service) getSubtreeThreads(ctx context.Context, input models.ForumGetThreads) ([]models.Thread, error) {
+	forums, err := s.forumStorage.GetSubtree(ctx, models.ForumInput{Slug: input.Slug})
 	if err != nil {
-		return []models.User{}, err
+		return []models.Thread{}, err
+	}
+	return s.threadStorage.GetThreadsByForums(ctx, input, forums)
+}
+
+// withUnread sets how many posts of each thread the viewer has not read.
+func (s service) withUnread(ctx context.Context, viewer string, threads []models.Thread) error {
+	user, err := s.userStorage.GetProfile(ctx, viewer)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, len(threads))
+	for i, thread := range threads {
+		ids[i] = thread.ID
+	}
+	unread, err := s.readMarkerStorage.CountUnread(ctx, user.Nickname, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range threads {
+		count := unread[threads[i].ID]
+		threads[i].Unread = &count
+	}
+	return nil
+}
+
+func (s service) GetForumUsers(ctx context.Context, input models.ForumGetUsers) ([]models.User, error) {
+	_, err := s.forumStorage.GetForumID(ctx, models.ForumInput{Slug: input.Slug})
+	if err != nil {
+		if input.Slug, err = s.forumAlias(ctx, input.Slug, err); err != nil {
+			return []models.User{}, err
+		}
 	}
 
 
 	if input.Limit == 0 {
 		input.Limit = math.MaxInt32
 	}
-	return s.userStorage.GetUsers(input, input.Slug)
+	return s.userStorage.GetUsers(ctx, input, input.Slug)
+}
+
+// UpdateForum changes the forum; a renamed forum keeps its old slug as an
+// alias, and a slug the forum itself had before is taken back from its aliases.
+func (s service) UpdateForum(ctx context.Context, input models.ForumUpdate) (models.Forum, error) {
+	var forum models.Forum
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		current, err := s.getForum(ctx, models.ForumInput{Slug: input.Slug})
+		if err != nil {
+			return err
+		}
+		input.Slug = current.Slug
+
+		if input.Parent != nil && *input.Parent != "" {
+			if err = s.checkForumParent(ctx, current.Slug, input.Parent); err != nil {
+				return err
+			}
+		}
+
+		renamed := input.NewSlug != "" && !strings.EqualFold(input.NewSlug, current.Slug)
+		if renamed {
+			owner, err := s.forumStorage.ResolveAlias(ctx, input.NewSlug)
+			switch {
+			case err == nil && !strings.EqualFold(owner, current.Slug):
+				return models.NewConflict(models.EntityForum, models.ReasonAlreadyExists, "slug is an alias of another forum")
+			case err == nil:
+				if err = s.forumStorage.RemoveAlias(ctx, input.NewSlug); err != nil {
+					return err
+				}
+			case !errors.Is(err, models.ErrNotFound):
+				return err
+			}
+		}
+
+		forum, err = s.forumStorage.UpdateForum(ctx, input)
+		if err != nil || !renamed {
+			return err
+		}
+
+		return s.forumStorage.AddAlias(ctx, current.Slug, forum.Slug)
+	})
+	if err != nil {
+		return models.Forum{}, err
+	}
+
+	return forum, nil
+}
+
+// checkForumParent resolves the new parent of the forum to its current slug and
+// makes sure it is neither the forum itself nor one of its descendants.
+func (s service) checkForumParent(ctx context.Context, slug string, parent *string) error {
+	forum, err := s.getForum(ctx, models.ForumInput{Slug: *parent})
+	if err != nil {
+		return err
+	}
+	*parent = forum.Slug
+
+	subtree, err := s.forumStorage.GetSubtree(ctx, models.ForumInput{Slug: slug})
+	if err != nil {
+		return err
+	}
+	for _, descendant := range subtree {
+		if strings.EqualFold(descendant, forum.Slug) {
+			return models.NewConflict(models.EntityForum, models.ReasonParentConflict, "forum cannot be moved under itself or its subforum")
+		}
+	}
+	return nil
 }
 
-func (s service) CreateUser(input models.User) ([]models.User, error) {
-	user, err := s.userStorage.CreateUser(input)
+// DeleteForum removes the forum and everything in it, or with dryRun only
+// reports what would be removed.
+func (s service) DeleteForum(ctx context.Context, input models.ForumInput, dryRun bool) (models.ForumDeletion, error) {
+	if dryRun {
+		deletion, err := s.forumStorage.CountForumContents(ctx, input)
+		if err != nil {
+			if input.Slug, err = s.forumAlias(ctx, input.Slug, err); err != nil {
+				return models.ForumDeletion{}, err
+			}
+			deletion, err = s.forumStorage.CountForumContents(ctx, input)
+		}
+		deletion.DryRun = true
+		return deletion, err
+	}
+
+	var deletion models.ForumDeletion
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		var err error
+		deletion, err = s.forumStorage.DeleteForum(ctx, input)
+		if err != nil {
+			if input.Slug, err = s.forumAlias(ctx, input.Slug, err); err != nil {
+				return err
+			}
+			deletion, err = s.forumStorage.DeleteForum(ctx, input)
+		}
+		return err
+	})
+	if err != nil {
+		return models.ForumDeletion{}, err
+	}
+
+	s.log.For(ctx).Info("forum deleted", "forum", deletion.Forum, "threads", deletion.Threads, "posts", deletion.Posts)
+	return deletion, nil
+}
+
+var errForumNotFound = models.NewNotFound(models.EntityForum)
+
+// forumAlias looks slug up among the old slugs of renamed forums when err
+// says that no forum has it, and returns the current slug. Any other err is
+// returned as is, as is err when there is no such alias either.
+func (s service) forumAlias(ctx context.Context, slug string, err error) (string, error) {
+	if !errors.Is(err, errForumNotFound) {
+		return "", err
+	}
+
+	forum, aliasErr := s.forumStorage.ResolveAlias(ctx, slug)
+	if aliasErr != nil {
+		if errors.Is(aliasErr, models.ErrNotFound) {
+			return "", err
+		}
+		return "", aliasErr
+	}
+
+	return forum, nil
+}
+
+func (s service) CreateUser(ctx context.Context, input models.User) ([]models.User, error) {
+	// The old nickname of a renamed user stays reserved for a while.
+	owner, err := s.userStorage.ResolveAlias(ctx, input.Nickname)
+	if err == nil {
+		ownerUser, err := s.userStorage.GetProfile(ctx, owner)
+		if err != nil {
+			return []models.User{}, err
+		}
+		return []models.User{ownerUser}, models.NewConflict(models.EntityUser, models.ReasonReserved, "nickname is reserved")
+	}
+	if !errors.Is(err, models.ErrNotFound) {
+		return []models.User{}, err
+	}
+
+	user, err := s.userStorage.CreateUser(ctx, input)
 
 	if err == nil {
 		return []models.User{user}, err
 	}
 
 	users := make([]models.User, 0)
-	if err.Error() == "409" {
-		userNick, err := s.userStorage.GetProfile(input.Nickname)
-		if err != nil && err.Error() != "404"{
+	if errors.Is(err, models.ErrConflict) {
+		conflict := err
+		userNick, err := s.userStorage.GetProfile(ctx, input.Nickname)
+		if err != nil && !errors.Is(err, models.ErrNotFound) {
 			return []models.User{}, err
 		}
 		if err == nil {
@@ -120,111 +427,614 @@ func (s service) CreateUser(input models.User) ([]models.User, error) {
 		}
 
 		if strings.ToLower(userNick.Email) == strings.ToLower(input.Email){
-			return users, models.Error{Code: "409", Message: "conflict"}
+			return users, conflict
 		}
 
-		userEmail, err := s.userStorage.GetEmailConflictUser(input.Email)
-		if err != nil && err.Error() != "404"{
+		userEmail, err := s.userStorage.GetEmailConflictUser(ctx, input.Email)
+		if err != nil && !errors.Is(err, models.ErrNotFound) {
 			return []models.User{}, err
 		}
 		if err == nil {
 			users = append(users, userEmail)
 		}
 
-		return users, models.Error{Code: "409", Message: "conflict"}
+		return users, conflict
 	}
 
 	return []models.User{}, err
 }
 
-func (s service) GetUser(nickname string) (models.User, error) {
-	return s.userStorage.GetProfile(nickname)
+// GetUser returns the profile of the user with the counters of what they
+// have written.
+func (s service) GetUser(ctx context.Context, nickname string) (models.User, error) {
+	user, err := s.userStorage.GetProfile(ctx, nickname)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	counters, err := s.userStorage.GetCounters(ctx, user.Nickname)
+	if err != nil {
+		return models.User{}, err
+	}
+	user.Counters = &counters
+
+	return user, nil
 }
 
-func (s service) UpdateUser(input models.User) (models.User, error) {
-	if input.Email == "" && input.Fullname == "" && input.About == "" {
-		return s.userStorage.GetProfile(input.Nickname)
+func (s service) GetUserThreads(ctx context.Context, input models.UserGetThreads) ([]models.Thread, error) {
+	var err error
+	if input.Nickname, input.Forum, err = s.checkUserListing(ctx, input.Nickname, input.Forum); err != nil {
+		return []models.Thread{}, err
+	}
+
+	if input.Limit == 0 {
+		input.Limit = math.MaxInt32
 	}
-	return s.userStorage.UpdateProfile(input)
+	return s.threadStorage.GetThreadsByUser(ctx, input)
 }
 
-func (s service) CreateThread(input models.Thread) (models.Thread, error) {
-	thread, err := s.threadStorage.CreateThread(input)
-	if err == nil {
-		err = s.forumStorage.UpdateThreadsCount(models.ForumInput{Slug: input.Forum})
-		if err != nil {
-			return models.Thread{}, err
+func (s service) GetUserPosts(ctx context.Context, input models.UserGetPosts) ([]models.Post, error) {
+	var err error
+	if input.Nickname, input.Forum, err = s.checkUserListing(ctx, input.Nickname, input.Forum); err != nil {
+		return []models.Post{}, err
+	}
+
+	if input.Limit == 0 {
+		input.Limit = math.MaxInt32
+	}
+	return s.postStorage.GetPostsByUser(ctx, input)
+}
+
+// GetUserMentions lists where the user was mentioned.
+func (s service) GetUserMentions(ctx context.Context, input models.UserGetMentions) ([]models.Mention, error) {
+	user, err := s.userStorage.GetProfile(ctx, input.Nickname)
+	if err != nil {
+		return []models.Mention{}, err
+	}
+	input.Nickname = user.Nickname
+
+	if input.Limit == 0 {
+		input.Limit = math.MaxInt32
+	}
+	return s.mentionStorage.GetMentions(ctx, input)
+}
+
+// checkUserListing makes sure the user and the forum, when one is given,
+// exist, and returns the nickname and slug as stored.
+func (s service) checkUserListing(ctx context.Context, nickname string, forum string) (string, string, error) {
+	user, err := s.userStorage.GetProfile(ctx, nickname)
+	if err != nil {
+		return "", "", err
+	}
+	if forum == "" {
+		return user.Nickname, "", nil
+	}
+
+	err = s.forumStorage.CheckIfForumExists(ctx, models.ForumInput{Slug: forum})
+	if err != nil {
+		if forum, err = s.forumAlias(ctx, forum, err); err != nil {
+			return "", "", err
 		}
+	}
+	return user.Nickname, forum, nil
+}
 
-		userID, err := s.userStorage.GetUserByNickname(input.Author)
+// RenameUser changes the nickname of a user everywhere it is used. The old
+// nickname stays reserved for the user for Options.NicknameAliasTTL.
+func (s service) RenameUser(ctx context.Context, input models.UserRename) (models.User, error) {
+	var user models.User
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		current, err := s.userStorage.GetProfile(ctx, input.Nickname)
 		if err != nil {
-			return models.Thread{}, err
+			return err
+		}
+		input.Nickname = current.Nickname
+
+		// A change of case only is not a new nickname and needs no alias.
+		renamed := !strings.EqualFold(input.NewNickname, current.Nickname)
+		if renamed {
+			owner, err := s.userStorage.ResolveAlias(ctx, input.NewNickname)
+			switch {
+			case err == nil && !strings.EqualFold(owner, current.Nickname):
+				return models.NewConflict(models.EntityUser, models.ReasonReserved, "nickname is reserved")
+			case err != nil && !errors.Is(err, models.ErrNotFound):
+				return err
+			}
+			// The nickname is free or one of the user's own; expired aliases
+			// are dropped along the way.
+			if err = s.userStorage.RemoveAlias(ctx, input.NewNickname); err != nil {
+				return err
+			}
+		}
+
+		user, err = s.userStorage.RenameUser(ctx, input)
+		if err != nil || !renamed || s.options.NicknameAliasTTL == 0 {
+			return err
 		}
 
-		forumID, err := s.forumStorage.GetForumSlug(input.Forum)
+		return s.userStorage.AddAlias(ctx, current.Nickname, user.Nickname, time.Now().Add(s.options.NicknameAliasTTL))
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	s.log.For(ctx).Info("user renamed", "from", input.Nickname, "to", user.Nickname)
+	return user, nil
+}
+
+// ResolveNickname returns the current nickname of a renamed user whose old
+// nickname is still reserved.
+func (s service) ResolveNickname(ctx context.Context, alias string) (string, error) {
+	return s.userStorage.ResolveAlias(ctx, alias)
+}
+
+// DeleteUser replaces the profile of the user with a pseudonym. In the
+// anonymize mode the content of the user stays as it is, so thread trees and
+// votes are kept. In the purge mode the threads, votes and edit revisions of
+// the user are removed, the posts become tombstones and the forum counters
+// follow.
+func (s service) DeleteUser(ctx context.Context, nickname string, mode string) (models.UserDeletion, error) {
+	deletion := models.UserDeletion{Mode: mode}
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		user, err := s.userStorage.GetProfile(ctx, nickname)
 		if err != nil {
-			return models.Thread{}, err
+			return err
+		}
+
+		if mode == models.DeletePurge {
+			if err = s.purgeUserContent(ctx, user.Nickname, &deletion); err != nil {
+				return err
+			}
+		}
+
+		// Subscriptions, notifications, mentions and read markers mean
+		// nothing without the user.
+		if err = s.notificationStorage.DeleteUser(ctx, user.Nickname); err != nil {
+			return err
 		}
+		if err = s.mentionStorage.DeleteUser(ctx, user.Nickname); err != nil {
+			return err
+		}
+		if err = s.readMarkerStorage.DeleteUser(ctx, user.Nickname); err != nil {
+			return err
+		}
+
+		deletion.Nickname, err = s.userStorage.AnonymizeUser(ctx, user.Nickname)
+		return err
+	})
+	if err != nil {
+		return models.UserDeletion{}, err
+	}
+
+	s.log.For(ctx).Info("user deleted", "nickname", deletion.Nickname, "mode", mode, "threads", deletion.Threads, "posts", deletion.Posts)
+	return deletion, nil
+}
+
+func (s service) purgeUserContent(ctx context.Context, nickname string, deletion *models.UserDeletion) error {
+	var err error
+	deletion.Votes, err = s.voteStorage.DeleteUserVotes(ctx, nickname)
+	if err != nil {
+		return err
+	}
+	postVotes, err := s.voteStorage.DeleteUserPostVotes(ctx, nickname)
+	if err != nil {
+		return err
+	}
+	deletion.Votes += postVotes
+
+	counters, threads, posts, err := s.threadStorage.PurgeAuthorThreads(ctx, nickname)
+	if err != nil {
+		return err
+	}
+	deletion.Threads = threads
+	if err = s.takeForumCounters(ctx, counters); err != nil {
+		return err
+	}
+
+	counters, tombstones, err := s.postStorage.TombstoneAuthorPosts(ctx, nickname)
+	if err != nil {
+		return err
+	}
+	deletion.Posts = posts + tombstones
+	if err = s.takeForumCounters(ctx, counters); err != nil {
+		return err
+	}
+
+	// Revisions keep the text around edits the user made to the content of
+	// others.
+	if err = s.postStorage.DeleteEditorRevisions(ctx, nickname); err != nil {
+		return err
+	}
+	if err = s.threadStorage.DeleteEditorRevisions(ctx, nickname); err != nil {
+		return err
+	}
+	if err = s.mentionStorage.DeleteAuthor(ctx, nickname); err != nil {
+		return err
+	}
+
+	return s.forumStorage.RemoveUser(ctx, nickname)
+}
 
-		err = s.forumStorage.AddUserToForum(userID, forumID)
-		if err != nil && err.Error() != "409" {
-			return models.Thread{}, err
+// takeForumCounters takes the counters off their forums.
+func (s service) takeForumCounters(ctx context.Context, counters []models.ForumCounters) error {
+	for _, counter := range counters {
+		forum := models.ForumInput{Slug: counter.Forum}
+		if counter.Threads > 0 {
+			if err := s.forumStorage.UpdateThreadsCount(ctx, forum, -counter.Threads); err != nil {
+				return err
+			}
+		}
+		if counter.Posts > 0 {
+			if err := s.forumStorage.UpdatePostsCount(ctx, forum, -counter.Posts); err != nil {
+				return err
+			}
 		}
+	}
+	return nil
+}
+
+// ExportUser collects everything stored about the user.
+func (s service) ExportUser(ctx context.Context, nickname string) (models.UserExport, error) {
+	var export models.UserExport
+	var err error
+	export.User, err = s.userStorage.GetProfile(ctx, nickname)
+	if err != nil {
+		return models.UserExport{}, err
+	}
+	nickname = export.User.Nickname
+
+	if export.Aliases, err = s.userStorage.GetAliases(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.Forums, err = s.forumStorage.GetForumsByOwner(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.Memberships, err = s.forumStorage.GetMemberships(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.Threads, err = s.threadStorage.GetThreadsByAuthor(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.Posts, err = s.postStorage.GetPostsByAuthor(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.PostRevisions, err = s.postStorage.GetEditorRevisions(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.ThreadRevisions, err = s.threadStorage.GetEditorRevisions(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.Votes, err = s.voteStorage.GetUserVotes(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.PostVotes, err = s.voteStorage.GetUserPostVotes(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.Reactions, err = s.voteStorage.GetUserReactions(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.Subscriptions, err = s.notificationStorage.GetUserSubscriptions(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.Notifications, err = s.notificationStorage.GetUserNotifications(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.Mentions, err = s.mentionStorage.GetUserMentions(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
+	if export.ReadMarkers, err = s.readMarkerStorage.GetUserMarkers(ctx, nickname); err != nil {
+		return models.UserExport{}, err
+	}
 
+	return export, nil
+}
+
+func (s service) UpdateUser(ctx context.Context, input models.User) (models.User, error) {
+	if input.Email == "" && input.Fullname == "" && input.About == "" {
+		return s.userStorage.GetProfile(ctx, input.Nickname)
+	}
+	return s.userStorage.UpdateProfile(ctx, input)
+}
+
+func (s service) CreateThread(ctx context.Context, input models.Thread) (models.Thread, error) {
+	input.Tags = normalizeTags(input.Tags)
+	thread, err := s.createThread(ctx, input)
+	if errors.Is(err, errForumNotFound) {
+		if input.Forum, err = s.forumAlias(ctx, input.Forum, err); err == nil {
+			thread, err = s.createThread(ctx, input)
+		}
+	}
+	if err == nil {
 		return thread, nil
 	}
 
-	if err.Error() == "409"  {
-		oldThread, err := s.threadStorage.GetDetails(models.ThreadInput{Slug: input.Slug})
-		if err == nil {
-			return oldThread, models.Error{Code: "409"}
+	// The conflicting insert aborted the transaction, so the existing thread
+	// is looked up outside of it.
+	if errors.Is(err, models.ErrConflict) {
+		oldThread, errOld := s.threadStorage.GetDetails(ctx, models.ThreadInput{Slug: input.Slug})
+		if errOld == nil {
+			return oldThread, err
 		}
-		return thread, err
+		return models.Thread{}, errOld
 	}
 
+	return models.Thread{}, err
+}
+
+func (s service) createThread(ctx context.Context, input models.Thread) (models.Thread, error) {
+	var thread models.Thread
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		mentions, unknown, err := s.checkMentions(ctx, "message", input.Message)
+		if err != nil {
+			return err
+		}
+
+		thread, err = s.threadStorage.CreateThread(ctx, input)
+		if err != nil {
+			return err
+		}
+		thread.UnknownMentions = unknown
+		if err = s.mentionStorage.ReplaceThreadMentions(ctx, thread, mentions); err != nil {
+			return err
+		}
+
+		err = s.forumStorage.UpdateThreadsCount(ctx, models.ForumInput{Slug: input.Forum}, 1)
+		if err != nil {
+			return err
+		}
+
+		userID, err := s.userStorage.GetUserByNickname(ctx, input.Author)
+		if err != nil {
+			return err
+		}
+
+		forumID, err := s.forumStorage.GetForumSlug(ctx, input.Forum)
+		if err != nil {
+			return err
+		}
+
+		if err = s.forumStorage.AddUserToForum(ctx, userID, forumID); err != nil {
+			return err
+		}
+
+		return s.notificationStorage.NotifyThread(ctx, thread)
+	})
+
 	return thread, err
 }
 
-func (s service) ThreadVote(input models.Vote) (models.Thread, error) {
-	thread, err := s.threadStorage.CheckThreadIfExists(input.Thread)
+func (s service) ThreadVote(ctx context.Context, input models.Vote) (models.Thread, error) {
+	var output models.Thread
+	// Every vote updates threads.votes, so votes on one thread queue on its
+	// row lock instead of failing each other under RepeatableRead. The old
+	// voice is read once the lock is held.
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		_, state, err := s.threadStorage.LockThread(ctx, &input.Thread)
+		if err != nil {
+			return err
+		}
+		if err = checkThreadState(state, false); err != nil {
+			return err
+		}
+
+		voice, found, err := s.voteStorage.GetVoice(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		// Repeating the same voice changes nothing.
+		if found && voice == input.Voice {
+			output, err = s.threadStorage.GetDetails(ctx, input.Thread)
+			return err
+		}
+
+		output, err = s.voteStorage.CreateVote(ctx, input, found)
+		if err != nil {
+			return err
+		}
+
+		return s.notificationStorage.NotifyVote(ctx, output.ID, input.User)
+	})
 	if err != nil {
 		return models.Thread{}, err
 	}
-	input.Thread = thread
 
-	var updateFlag bool
+	return output, nil
+}
+
+func (s service) GetThread(ctx context.Context, input models.ThreadInput, viewer string) (models.Thread, error) {
+	thread, err := s.threadStorage.GetDetails(ctx, input)
+	if err != nil || viewer == "" {
+		return thread, err
+	}
+
+	threads := []models.Thread{thread}
+	if err = s.withUnread(ctx, viewer, threads); err != nil {
+		return models.Thread{}, err
+	}
+	return threads[0], nil
+}
+
+// MarkThreadRead moves the read marker of the user in the thread forward to
+// the post, or to the last post when none is given.
+func (s service) MarkThreadRead(ctx context.Context, thread models.ThreadInput, input models.ReadMarker) (models.ReadMarker, error) {
+	if _, _, err := s.threadStorage.GetForumByThread(ctx, &thread); err != nil {
+		return models.ReadMarker{}, err
+	}
+	input.Thread = thread.ThreadID
+
+	user, err := s.userStorage.GetProfile(ctx, input.User)
+	if err != nil {
+		return models.ReadMarker{}, err
+	}
+	input.User = user.Nickname
+
+	if input.Post != 0 {
+		var post models.Post
+		if err = s.postStorage.GetPostDetails(ctx, models.PostInput{ID: input.Post}, &post); err != nil {
+			return models.ReadMarker{}, err
+		}
+		if post.ThreadInput.ThreadID != input.Thread {
+			return models.ReadMarker{}, models.NewNotFound(models.EntityPost)
+		}
+	}
 
-	checkThread, err := s.voteStorage.CheckDoubleVote(input)
+	if input.Post, err = s.readMarkerStorage.MarkRead(ctx, input); err != nil {
+		return models.ReadMarker{}, err
+	}
+	unread, err := s.readMarkerStorage.CountUnread(ctx, input.User, []int{input.Thread})
 	if err != nil {
-		if err.Error() == "409" {
-			return checkThread, nil
+		return models.ReadMarker{}, err
+	}
+	input.Unread = unread[input.Thread]
+
+	return input, nil
+}
+
+func (s service) UpdateThread(ctx context.Context, input models.ThreadUpdate) (models.Thread, error) {
+	var thread models.Thread
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		_, state, err := s.threadStorage.GetForumByThread(ctx, &input.ThreadInput)
+		if err != nil {
+			return err
+		}
+		if err = checkThreadState(state, true); err != nil {
+			return err
 		}
-		if err.Error() == "500" {
-			return models.Thread{}, err
+
+		mentions, unknown, err := s.checkMentions(ctx, "message", input.Message)
+		if err != nil {
+			return err
 		}
-		if err.Error() == "101" {
-			updateFlag = true
+		if thread, err = s.threadStorage.UpdateThread(ctx, input); err != nil {
+			return err
 		}
+
+		if input.Tags != nil {
+			thread.Tags = normalizeTags(input.Tags)
+			if err = s.threadStorage.SetTags(ctx, thread.ID, thread.Tags); err != nil {
+				return err
+			}
+		}
+
+		if input.Message == "" {
+			return nil
+		}
+		thread.UnknownMentions = unknown
+		return s.mentionStorage.ReplaceThreadMentions(ctx, thread, mentions)
+	})
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	return thread, nil
+}
+
+// GetThreadHistory lists the changes of the title and message of the thread.
+func (s service) GetThreadHistory(ctx context.Context, input models.ThreadGetHistory) ([]models.ThreadRevision, error) {
+	if _, _, err := s.threadStorage.GetForumByThread(ctx, &input.ThreadInput); err != nil {
+		return nil, err
+	}
+
+	if input.Limit == 0 {
+		input.Limit = math.MaxInt32
 	}
+	return s.threadStorage.GetRevisions(ctx, input)
+}
 
-	output, err := s.voteStorage.CreateVote(input, updateFlag)
+func (s service) SetThreadState(ctx context.Context, input models.ThreadState) (models.Thread, error) {
+	thread, err := s.threadStorage.SetState(ctx, input)
 	if err != nil {
 		return models.Thread{}, err
 	}
 
-	return output, nil
+	s.log.For(ctx).Info("thread state changed", "thread", thread.ID, "state", thread.State)
+	return thread, nil
+}
+
+// DeleteThread marks the thread deleted and takes it, its live posts and the
+// users who only took part in it out of the forum.
+func (s service) DeleteThread(ctx context.Context, input models.ThreadInput) (models.Thread, error) {
+	var thread models.Thread
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		var posts int
+		var err error
+		thread, posts, err = s.threadStorage.DeleteThread(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		forum := models.ForumInput{Slug: thread.Forum}
+		if err = s.forumStorage.UpdateThreadsCount(ctx, forum, -1); err != nil {
+			return err
+		}
+		if posts > 0 {
+			if err = s.forumStorage.UpdatePostsCount(ctx, forum, -posts); err != nil {
+				return err
+			}
+		}
+
+		return s.forumStorage.RemoveThreadUsers(ctx, thread.Forum, thread.ID)
+	})
+	if err != nil {
+		return models.Thread{}, err
+	}
+
+	s.log.For(ctx).Info("thread deleted", "thread", thread.ID, "forum", thread.Forum)
+	return thread, nil
+}
+
+// normalizeTags lower-cases the tags and drops repeats, keeping nil as nil.
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(tag)
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// GetTagThreads lists the threads with the tag in every forum. A tag no
+// thread has yields an empty list.
+func (s service) GetTagThreads(ctx context.Context, input models.TagGetThreads) ([]models.Thread, error) {
+	input.Tag = strings.ToLower(input.Tag)
+	if input.Limit == 0 {
+		input.Limit = math.MaxInt32
+	}
+	return s.threadStorage.GetThreadsByTag(ctx, input)
 }
 
-func (s service) GetThread(input models.ThreadInput) (models.Thread, error) {
-	return s.threadStorage.GetDetails(input)
+func (s service) GetTags(ctx context.Context, input models.TagsGet) ([]models.Tag, error) {
+	input.Prefix = strings.ToLower(input.Prefix)
+	if input.Limit == 0 {
+		input.Limit = math.MaxInt32
+	}
+	return s.threadStorage.GetTags(ctx, input)
 }
 
-func (s service) UpdateThread(input models.ThreadUpdate) (models.Thread, error) {
-	return s.threadStorage.UpdateThread(input)
+// checkThreadState rejects changes the state of a thread does not allow:
+// locked threads still accept edits but no new posts or votes, archived
+// threads accept nothing.
+func checkThreadState(state string, edit bool) error {
+	switch {
+	case state == models.ThreadArchived:
+		return models.NewConflict(models.EntityThread, models.ReasonArchived, "thread is archived")
+	case state == models.ThreadLocked && !edit:
+		return models.NewConflict(models.EntityThread, models.ReasonLocked, "thread is locked")
+	}
+	return nil
 }
 
-func (s service) GetThreadPosts(input models.ThreadGetPosts) ([]models.Post, error) {
-	thread, err := s.threadStorage.CheckThreadIfExists(input.ThreadInput)
+func (s service) GetThreadPosts(ctx context.Context, input models.ThreadGetPosts) ([]models.Post, error) {
+	thread, err := s.threadStorage.CheckThreadIfExists(ctx, input.ThreadInput)
 	if err != nil {
 		return []models.Post{}, err
 	}
@@ -234,10 +1044,59 @@ func (s service) GetThreadPosts(input models.ThreadGetPosts) ([]models.Post, err
 	if input.Limit == 0 {
 		input.Limit = math.MaxInt32
 	}
-	return s.postStorage.GetPostsByThread(input)
+	return s.postStorage.GetPostsByThread(ctx, input)
+}
+
+func (s service) CreatePosts(ctx context.Context, thread models.ThreadInput, posts []models.PostCreate) ([]models.Post, error) {
+	created := make([]models.Post, 0)
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		forum, state, err := s.threadStorage.GetForumByThread(ctx, &thread)
+		if err != nil {
+			return err
+		}
+		if err = checkThreadState(state, false); err != nil {
+			return err
+		}
+
+		if len(posts) == 0 {
+			return nil
+		}
+
+		mentions := make([][]string, len(posts))
+		unknown := make([][]string, len(posts))
+		for i, post := range posts {
+			mentions[i], unknown[i], err = s.checkMentions(ctx, fmt.Sprintf("posts[%d].message", i), post.Message)
+			if err != nil {
+				return err
+			}
+		}
+
+		created, err = s.postStorage.CreatePosts(ctx, thread, forum, time.Now().Format(time.RFC3339Nano), posts)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]int, len(created))
+		for i, post := range created {
+			ids[i] = post.ID
+			created[i].UnknownMentions = unknown[i]
+			if len(mentions[i]) == 0 {
+				continue
+			}
+			if err = s.mentionStorage.ReplacePostMentions(ctx, post, mentions[i]); err != nil {
+				return err
+			}
+		}
+		return s.notificationStorage.NotifyPosts(ctx, ids)
+	})
+	if err != nil {
+		return []models.Post{}, err
+	}
+
+	return created, nil
 }
 
-func (s service) GetPost(id int, related string) (models.PostFull, error) {
+func (s service) GetPost(ctx context.Context, id int, related string) (models.PostFull, error) {
 	postFull := models.PostFull{
 		Author: nil,
 		Forum:  nil,
@@ -245,7 +1104,7 @@ func (s service) GetPost(id int, related string) (models.PostFull, error) {
 		Thread: nil,
 	}
 	post := new(models.Post)
-	err := s.postStorage.GetPostDetails(models.PostInput{ID: id}, post)
+	err := s.postStorage.GetPostDetails(ctx, models.PostInput{ID: id}, post)
 	postFull.Post = post
 	if err != nil {
 		return models.PostFull{}, err
@@ -253,7 +1112,7 @@ func (s service) GetPost(id int, related string) (models.PostFull, error) {
 
 	author := new(models.User)
 	if strings.Contains(related, "user") {
-		err = s.userStorage.GetUserForPost(postFull.Post.Author, author)
+		err = s.userStorage.GetUserForPost(ctx, postFull.Post.Author, author)
 		postFull.Author = author
 		if err != nil {
 			return models.PostFull{}, err
@@ -262,7 +1121,7 @@ func (s service) GetPost(id int, related string) (models.PostFull, error) {
 
 	forum := new(models.Forum)
 	if strings.Contains(related, "forum") {
-		err = s.forumStorage.GetForumForPost(postFull.Post.Forum, forum)
+		err = s.forumStorage.GetForumForPost(ctx, postFull.Post.Forum, forum)
 		postFull.Forum = forum
 		if err != nil {
 			return models.PostFull{}, err
@@ -271,31 +1130,379 @@ func (s service) GetPost(id int, related string) (models.PostFull, error) {
 
 	thread := new(models.Thread)
 	if strings.Contains(related, "thread") {
-		err = s.threadStorage.GetThreadForPost(postFull.Post.ThreadInput, thread)
+		err = s.threadStorage.GetThreadForPost(ctx, postFull.Post.ThreadInput, thread)
 		postFull.Thread = thread
 		if err != nil {
 			return models.PostFull{}, err
 		}
 	}
 
+	if strings.Contains(related, "history") {
+		postFull.History, err = s.postRevisions(ctx, models.PostGetHistory{ID: id, Limit: models.PostFullHistory, Desc: true})
+		if err != nil {
+			return models.PostFull{}, err
+		}
+	}
+
 	return postFull, nil
 }
 
-func (s service) UpdatePost(input models.PostUpdate) (models.Post, error) {
-	return s.postStorage.UpdatePost(input)
+func (s service) UpdatePost(ctx context.Context, input models.PostUpdate) (models.Post, error) {
+	var post models.Post
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		if err := s.checkPostThread(ctx, input.ID); err != nil {
+			return err
+		}
+
+		mentions, unknown, err := s.checkMentions(ctx, "message", input.Message)
+		if err != nil {
+			return err
+		}
+		if post, err = s.postStorage.UpdatePost(ctx, input); err != nil {
+			return err
+		}
+		if input.Message == "" {
+			return nil
+		}
+		post.UnknownMentions = unknown
+		return s.mentionStorage.ReplacePostMentions(ctx, post, mentions)
+	})
+	if err != nil {
+		return models.Post{}, err
+	}
+
+	return post, nil
+}
+
+// GetPostHistory lists the earlier messages of the post with the changes each
+// edit made.
+func (s service) GetPostHistory(ctx context.Context, input models.PostGetHistory) ([]models.PostRevision, error) {
+	var post models.Post
+	if err := s.postStorage.GetPostDetails(ctx, models.PostInput{ID: input.ID}, &post); err != nil {
+		return nil, err
+	}
+
+	if input.Limit == 0 {
+		input.Limit = math.MaxInt32
+	}
+	return s.postRevisions(ctx, input)
+}
+
+func (s service) postRevisions(ctx context.Context, input models.PostGetHistory) ([]models.PostRevision, error) {
+	revisions, err := s.postStorage.GetRevisions(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range revisions {
+		revisions[i].Diff = diffLines(revisions[i].Message, revisions[i].Next)
+	}
+	return revisions, nil
+}
+
+func (s service) DeletePost(ctx context.Context, id int) (models.Post, error) {
+	var post models.Post
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		if err := s.checkPostThread(ctx, id); err != nil {
+			return err
+		}
+
+		var deleted bool
+		var err error
+		post, deleted, err = s.postStorage.DeletePost(ctx, models.PostInput{ID: id})
+		if err != nil || !deleted {
+			return err
+		}
+		if err = s.mentionStorage.ReplacePostMentions(ctx, post, nil); err != nil {
+			return err
+		}
+
+		return s.forumStorage.UpdatePostsCount(ctx, models.ForumInput{Slug: post.Forum}, -1)
+	})
+	if err != nil {
+		return models.Post{}, err
+	}
+
+	return post, nil
+}
+
+// checkPostThread makes sure the thread of the post still accepts edits.
+func (s service) checkPostThread(ctx context.Context, id int) error {
+	var post models.Post
+	if err := s.postStorage.GetPostDetails(ctx, models.PostInput{ID: id}, &post); err != nil {
+		return err
+	}
+
+	_, state, err := s.threadStorage.GetForumByThread(ctx, &post.ThreadInput)
+	if err != nil {
+		return err
+	}
+	return checkThreadState(state, true)
+}
+
+func (s service) PurgePostSubtree(ctx context.Context, id int) (models.PostsPurged, error) {
+	var purged models.PostsPurged
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		forum, posts, live, err := s.postStorage.PurgePostSubtree(ctx, models.PostInput{ID: id})
+		if err != nil {
+			return err
+		}
+		purged.Posts = posts
+
+		if live == 0 {
+			return nil
+		}
+		return s.forumStorage.UpdatePostsCount(ctx, models.ForumInput{Slug: forum}, -live)
+	})
+	if err != nil {
+		return models.PostsPurged{}, err
+	}
+
+	s.log.For(ctx).Info("post subtree purged", "post", id, "posts", purged.Posts)
+	return purged, nil
+}
+
+// PostVote votes for the post the way ThreadVote does for threads: repeating
+// the voice changes nothing and the other voice takes the first one back.
+func (s service) PostVote(ctx context.Context, input models.PostVote) (models.Post, error) {
+	var post models.Post
+	// Like thread votes, votes on one post queue on its row lock, and the
+	// post and the old voice are read once the lock is held.
+	err := s.unitOfWork.Do(ctx, pgx.ReadCommitted, func(ctx context.Context) error {
+		err := s.postStorage.LockPost(ctx, models.PostInput{ID: input.Post})
+		if err != nil {
+			return err
+		}
+		if post, err = s.checkPostVote(ctx, input.Post); err != nil {
+			return err
+		}
+
+		voice, found, err := s.voteStorage.GetPostVoice(ctx, input)
+		if err != nil {
+			return err
+		}
+		if found && voice == input.Voice {
+			return nil
+		}
+
+		score := input.Voice
+		if found {
+			score *= 2
+		}
+		if err = s.voteStorage.CreatePostVote(ctx, input, score); err != nil {
+			return err
+		}
+		post.Score += score
+		return nil
+	})
+	if err != nil {
+		return models.Post{}, err
+	}
+
+	return s.withReactions(ctx, post)
+}
+
+func (s service) AddPostReaction(ctx context.Context, input models.PostReaction) (models.Post, error) {
+	post, err := s.checkPostVote(ctx, input.Post)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if err = s.voteStorage.AddReaction(ctx, input); err != nil {
+		return models.Post{}, err
+	}
+
+	return s.withReactions(ctx, post)
+}
+
+// RemovePostReaction takes the reaction of the user back; there may be none.
+func (s service) RemovePostReaction(ctx context.Context, input models.PostReaction) (models.Post, error) {
+	post, err := s.checkPostVote(ctx, input.Post)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if err = s.voteStorage.RemoveReaction(ctx, input); err != nil {
+		return models.Post{}, err
+	}
+
+	return s.withReactions(ctx, post)
+}
+
+// checkPostVote returns the post when it can take votes and reactions: it is
+// not a tombstone and its thread takes votes.
+func (s service) checkPostVote(ctx context.Context, id int) (models.Post, error) {
+	var post models.Post
+	if err := s.postStorage.GetPostDetails(ctx, models.PostInput{ID: id}, &post); err != nil {
+		return models.Post{}, err
+	}
+	if post.IsDeleted {
+		return models.Post{}, models.NewConflict(models.EntityPost, models.ReasonDeleted, "post is deleted")
+	}
+
+	_, state, err := s.threadStorage.GetForumByThread(ctx, &post.ThreadInput)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if err = checkThreadState(state, false); err != nil {
+		return models.Post{}, err
+	}
+
+	return post, nil
+}
+
+func (s service) withReactions(ctx context.Context, post models.Post) (models.Post, error) {
+	reactions, err := s.voteStorage.GetReactions(ctx, post.ID)
+	if err != nil {
+		return models.Post{}, err
+	}
+	post.Reactions = reactions
+
+	return post, nil
+}
+
+// Search finds posts and threads by their text. A page that is not the last
+// one comes with the cursor of the next.
+func (s service) Search(ctx context.Context, input models.SearchInput) (models.SearchResult, error) {
+	if input.Limit == 0 {
+		input.Limit = models.SearchDefaultLimit
+	}
+	limit := input.Limit
+	// One hit more tells whether there is a next page.
+	input.Limit++
+
+	hits, err := s.searchStorage.Search(ctx, input)
+	if err != nil {
+		return models.SearchResult{}, err
+	}
+
+	result := models.SearchResult{Hits: hits}
+	if len(hits) > limit {
+		result.Hits = hits[:limit]
+		last := result.Hits[limit-1]
+		result.Next = models.SearchCursor{Rank: last.Rank, Kind: last.Kind, ID: last.ID}.String()
+	}
+	return result, nil
+}
+
+func (s service) SubscribeThread(ctx context.Context, thread models.ThreadInput, input models.Subscription) (models.Subscription, error) {
+	input, err := s.threadSubscription(ctx, thread, input)
+	if err != nil {
+		return models.Subscription{}, err
+	}
+
+	return input, s.notificationStorage.SubscribeThread(ctx, input)
+}
+
+func (s service) UnsubscribeThread(ctx context.Context, thread models.ThreadInput, input models.Subscription) (models.Subscription, error) {
+	input, err := s.threadSubscription(ctx, thread, input)
+	if err != nil {
+		return models.Subscription{}, err
+	}
+
+	return input, s.notificationStorage.UnsubscribeThread(ctx, input)
+}
+
+// threadSubscription fills the subscription in with the ID of the thread and
+// the nickname of the user as stored.
+func (s service) threadSubscription(ctx context.Context, thread models.ThreadInput, input models.Subscription) (models.Subscription, error) {
+	if _, _, err := s.threadStorage.GetForumByThread(ctx, &thread); err != nil {
+		return models.Subscription{}, err
+	}
+	user, err := s.userStorage.GetProfile(ctx, input.User)
+	if err != nil {
+		return models.Subscription{}, err
+	}
+
+	return models.Subscription{User: user.Nickname, Thread: thread.ThreadID}, nil
+}
+
+func (s service) SubscribeForum(ctx context.Context, input models.Subscription) (models.Subscription, error) {
+	input, err := s.forumSubscription(ctx, input)
+	if err != nil {
+		return models.Subscription{}, err
+	}
+
+	return input, s.notificationStorage.SubscribeForum(ctx, input)
 }
 
-func (s service) Clear() {
-	err := s.databaseService.Clear()
+func (s service) UnsubscribeForum(ctx context.Context, input models.Subscription) (models.Subscription, error) {
+	input, err := s.forumSubscription(ctx, input)
 	if err != nil {
-		fmt.Println(err)
+		return models.Subscription{}, err
 	}
+
+	return input, s.notificationStorage.UnsubscribeForum(ctx, input)
 }
 
-func (s service) Status() models.Status {
-	status, err := s.databaseService.Status()
+func (s service) forumSubscription(ctx context.Context, input models.Subscription) (models.Subscription, error) {
+	forum, err := s.GetForum(ctx, models.ForumInput{Slug: input.Forum})
 	if err != nil {
-		fmt.Println(err)
+		return models.Subscription{}, err
+	}
+	user, err := s.userStorage.GetProfile(ctx, input.User)
+	if err != nil {
+		return models.Subscription{}, err
+	}
+
+	return models.Subscription{User: user.Nickname, Forum: forum.Slug}, nil
+}
+
+// GetNotifications returns a page of the inbox of the user, newest first,
+// with the number of unread notifications in all of it.
+func (s service) GetNotifications(ctx context.Context, input models.UserGetNotifications) (models.Notifications, error) {
+	user, err := s.userStorage.GetProfile(ctx, input.Nickname)
+	if err != nil {
+		return models.Notifications{}, err
+	}
+	input.Nickname = user.Nickname
+
+	if input.Limit == 0 {
+		input.Limit = math.MaxInt32
+	}
+	notifications, err := s.notificationStorage.GetNotifications(ctx, input)
+	if err != nil {
+		return models.Notifications{}, err
+	}
+	unread, err := s.notificationStorage.CountUnread(ctx, input.Nickname)
+	if err != nil {
+		return models.Notifications{}, err
+	}
+
+	return models.Notifications{Unread: unread, Notifications: notifications}, nil
+}
+
+func (s service) MarkNotificationsRead(ctx context.Context, input models.NotificationsRead) (models.UnreadCount, error) {
+	user, err := s.userStorage.GetProfile(ctx, input.Nickname)
+	if err != nil {
+		return models.UnreadCount{}, err
+	}
+	input.Nickname = user.Nickname
+
+	if err = s.notificationStorage.MarkRead(ctx, input); err != nil {
+		return models.UnreadCount{}, err
+	}
+	unread, err := s.notificationStorage.CountUnread(ctx, input.Nickname)
+	if err != nil {
+		return models.UnreadCount{}, err
+	}
+
+	return models.UnreadCount{Unread: unread}, nil
+}
+
+func (s service) Clear(ctx context.Context) {
+	err := s.databaseService.Clear(ctx)
+	if err != nil {
+		s.log.For(ctx).Error("clearing the database", "error", err)
+	}
+}
+
+func (s service) Status(ctx context.Context) models.Status {
+	status, err := s.databaseService.Status(ctx)
+	if err != nil {
+		s.log.For(ctx).Error("reading the database status", "error", err)
 	}
 	return status
 }
+
+func (s service) SlowQueries(ctx context.Context) []models.SlowQuery {
+	return s.databaseService.SlowQueries(ctx)
+}
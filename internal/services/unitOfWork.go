@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EgorAist/TP_DB_project/internal/storages/dbConn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx"
+)
+
+const (
+	maxTxAttempts = 3
+	txRetryDelay  = 10 * time.Millisecond
+)
+
+// UnitOfWork runs several storage calls in one transaction. Storages pick
+// the transaction up from the context passed to fn, so they need no changes
+// to take part in it.
+type UnitOfWork interface {
+	// Do runs fn in a transaction with the given isolation level and commits
+	// it if fn returns nil. fn is retried from scratch when the transaction
+	// fails with a serialization failure or a deadlock, so it must not have
+	// side effects outside the database. Calling Do inside another unit of
+	// work joins the outer transaction.
+	Do(ctx context.Context, isolation pgx.TxIsoLevel, fn func(ctx context.Context) error) error
+}
+
+type unitOfWork struct {
+	db *dbConn.DB
+}
+
+func NewUnitOfWork(db *dbConn.DB) UnitOfWork {
+	return &unitOfWork{
+		db: db,
+	}
+}
+
+func (u unitOfWork) Do(ctx context.Context, isolation pgx.TxIsoLevel, fn func(ctx context.Context) error) (err error) {
+	if dbConn.InTx(ctx) {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = u.attempt(ctx, isolation, fn)
+		if err == nil || !retryable(err) || attempt == maxTxAttempts {
+			return err
+		}
+
+		select {
+		case <-time.After(time.Duration(attempt) * txRetryDelay):
+		case <-ctx.Done():
+			return dbConn.InternalError(ctx.Err())
+		}
+	}
+}
+
+func (u unitOfWork) attempt(ctx context.Context, isolation pgx.TxIsoLevel, fn func(ctx context.Context) error) error {
+	tx, err := u.db.Begin(ctx, isolation)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+
+	err = fn(dbConn.WithTx(ctx, tx))
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return dbConn.InternalError(err)
+	}
+
+	return nil
+}
+
+func retryable(err error) bool {
+	var pgErr pgx.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == pgerrcode.SerializationFailure || pgErr.Code == pgerrcode.DeadlockDetected
+}
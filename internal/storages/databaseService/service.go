@@ -1,40 +1,48 @@
 package databaseService
 
 import (
+	"context"
 	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/EgorAist/TP_DB_project/internal/storages/dbConn"
 	"github.com/jackc/pgx"
 )
 
 type Service interface {
-	Clear() (err error)
-	Status() (status models.Status, err error)
+	Clear(ctx context.Context) (err error)
+	Status(ctx context.Context) (status models.Status, err error)
+	SlowQueries(ctx context.Context) []models.SlowQuery
 }
 
 type service struct {
-	db *pgx.ConnPool
+	db *dbConn.DB
 
 }
 
-func NewStorage(db *pgx.ConnPool) Service {
+func NewStorage(db *dbConn.DB) Service {
 	return &service{
 		db: db,
 	}
 }
 
-func (s *service) Clear() (err error) {
-	_, err = s.db.Exec("TRUNCATE users, forums, threads, posts, forum_users, votes CASCADE")
+func (s *service) Clear(ctx context.Context) (err error) {
+	_, err = s.db.Exec(ctx, "TRUNCATE users, forums, threads, posts, forum_users, votes, tags CASCADE")
 	if err != nil {
-		return models.Error{Code: "500"}
+		return dbConn.InternalError(err)
 	}
 	return
 }
 
-func (s *service) Status() (status models.Status, err error) {
-	err = s.db.QueryRow("SELECT (SELECT COUNT(*) FROM forums), (SELECT COUNT(*) FROM threads), (SELECT COUNT(*) FROM posts), (SELECT COUNT(*) FROM users)").
+func (s *service) Status(ctx context.Context) (status models.Status, err error) {
+	err = s.db.QueryRow(ctx, "SELECT (SELECT COUNT(*) FROM forums), (SELECT COUNT(*) FROM threads WHERE state <> 'deleted'), " +
+		"(SELECT COUNT(*) FROM posts p WHERE NOT p.deleted AND NOT EXISTS (SELECT 1 FROM threads t WHERE t.ID = p.thread AND t.state = 'deleted')), (SELECT COUNT(*) FROM users)").
 				Scan(&status.Forum, &status.Thread, &status.Post, &status.User)
 	if err != nil && err != pgx.ErrNoRows {
-		return status, models.Error{Code: "500"}
+		return status, dbConn.InternalError(err)
 	}
 
 	return
-}
\ No newline at end of file
+}
+
+func (s *service) SlowQueries(ctx context.Context) []models.SlowQuery {
+	return s.db.SlowQueries()
+}
@@ -0,0 +1,280 @@
+package dbConn
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/EgorAist/TP_DB_project/internal/logger"
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx"
+)
+
+// DB is the connection pool shared by all storages. Every query takes the
+// request context, so a cancelled or timed out request releases its
+// connection instead of holding it until the query finishes.
+//
+// When the context carries a transaction (see WithTx), queries run inside
+// it instead of on a fresh pool connection.
+//
+// Connections are acquired explicitly so that the time spent waiting for
+// one is measured, and every query is timed per calling storage method
+// until its connection is released, rows included.
+type DB struct {
+	pool  *pgx.ConnPool
+	log   *logger.Logger
+	slow  SlowQueryConfig
+	plans *PlanStore
+}
+
+func New(pool *pgx.ConnPool, log *logger.Logger, slow SlowQueryConfig) *DB {
+	registerPoolMetrics(pool)
+	return &DB{
+		pool:  pool,
+		log:   log,
+		slow:  slow,
+		plans: newPlanStore(maxPlans),
+	}
+}
+
+// querier is implemented by both *pgx.Conn and *pgx.Tx.
+type querier interface {
+	ExecEx(ctx context.Context, sql string, options *pgx.QueryExOptions, args ...interface{}) (pgx.CommandTag, error)
+	QueryEx(ctx context.Context, sql string, options *pgx.QueryExOptions, args ...interface{}) (*pgx.Rows, error)
+}
+
+type txKey struct{}
+
+// WithTx returns a context whose queries run inside tx.
+func WithTx(ctx context.Context, tx *Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// InTx reports whether ctx already carries a transaction.
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*Tx)
+	return ok
+}
+
+// conn returns the transaction carried by ctx or a connection acquired from
+// the pool, and the function giving it back.
+func (db *DB) conn(ctx context.Context) (querier, func(), error) {
+	if tx, ok := ctx.Value(txKey{}).(*Tx); ok {
+		return tx.Tx, func() {}, nil
+	}
+
+	c, err := db.acquire(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, func() { db.pool.Release(c) }, nil
+}
+
+func (db *DB) acquire(ctx context.Context) (*pgx.Conn, error) {
+	started := time.Now()
+	c, err := db.pool.AcquireEx(ctx)
+	acquireDuration.Observe(time.Since(started).Seconds())
+	return c, err
+}
+
+func (db *DB) Exec(ctx context.Context, sql string, args ...interface{}) (pgx.CommandTag, error) {
+	method := callerMethod()
+	done := db.observe(ctx, method, sql, args)
+	defer done()
+
+	q, release, err := db.conn(ctx)
+	if err != nil {
+		db.logError(ctx, method, sql, err)
+		return "", err
+	}
+	defer release()
+
+	tag, err := q.ExecEx(ctx, sql, nil, args...)
+	db.logError(ctx, method, sql, err)
+	return tag, err
+}
+
+func (db *DB) Query(ctx context.Context, sql string, args ...interface{}) (*Rows, error) {
+	return db.query(ctx, callerMethod(), sql, args...)
+}
+
+func (db *DB) QueryRow(ctx context.Context, sql string, args ...interface{}) *Row {
+	rows, err := db.query(ctx, callerMethod(), sql, args...)
+	return &Row{rows: rows, err: err}
+}
+
+func (db *DB) query(ctx context.Context, method string, sql string, args ...interface{}) (*Rows, error) {
+	done := db.observe(ctx, method, sql, args)
+
+	q, release, err := db.conn(ctx)
+	if err != nil {
+		done()
+		db.logError(ctx, method, sql, err)
+		return nil, err
+	}
+
+	rows, err := q.QueryEx(ctx, sql, nil, args...)
+	if err != nil {
+		release()
+		done()
+		db.logError(ctx, method, sql, err)
+		return nil, err
+	}
+
+	r := &Rows{Rows: rows}
+	r.release = func() {
+		release()
+		done()
+		db.logError(ctx, method, sql, rows.Err())
+	}
+	return r, nil
+}
+
+// Rows gives the connection back once the rows are read or closed and logs
+// the error the query ended with, if any.
+type Rows struct {
+	*pgx.Rows
+	release func()
+}
+
+func (r *Rows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.done()
+	return false
+}
+
+func (r *Rows) Close() {
+	r.Rows.Close()
+	r.done()
+}
+
+func (r *Rows) done() {
+	if r.release != nil {
+		r.release()
+		r.release = nil
+	}
+}
+
+// Row is the result of QueryRow; like pgx.Row it reports pgx.ErrNoRows from
+// Scan when the query returned nothing.
+type Row struct {
+	rows *Rows
+	err  error
+}
+
+func (r *Row) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.done()
+	return (*pgx.Row)(r.rows.Rows).Scan(dest...)
+}
+
+// Tx is a transaction holding its connection until Commit or Rollback.
+type Tx struct {
+	*pgx.Tx
+	release func()
+}
+
+func (db *DB) Begin(ctx context.Context, isolation pgx.TxIsoLevel) (*Tx, error) {
+	c, err := db.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.BeginEx(ctx, &pgx.TxOptions{IsoLevel: isolation})
+	if err != nil {
+		db.pool.Release(c)
+		return nil, err
+	}
+
+	return &Tx{Tx: tx, release: func() { db.pool.Release(c) }}, nil
+}
+
+func (tx *Tx) Commit(ctx context.Context) error {
+	defer tx.release()
+	return tx.CommitEx(ctx)
+}
+
+func (tx *Tx) Rollback() error {
+	defer tx.release()
+	return tx.Tx.Rollback()
+}
+
+// InternalError converts an unexpected query error into the error returned
+// to the services: a timeout when the request ran out of time, unavailable
+// when it was cancelled or no connection could be acquired, internal
+// otherwise. The original error is kept for logging.
+func InternalError(err error) models.Error {
+	switch err {
+	case context.DeadlineExceeded:
+		return models.Error{Kind: models.KindTimeout, Reason: models.ReasonTimeout, Message: "query timed out", Err: err}
+	case context.Canceled:
+		return models.Error{Kind: models.KindUnavailable, Reason: models.ReasonCancelled, Message: "request cancelled", Err: err}
+	case pgx.ErrAcquireTimeout:
+		return models.Error{Kind: models.KindUnavailable, Reason: models.ReasonNoConnection, Message: "no database connection available", Err: err}
+	}
+
+	if pgErr, ok := err.(pgx.PgError); ok && pgErr.Code == pgerrcode.QueryCanceled {
+		return models.Error{Kind: models.KindTimeout, Reason: models.ReasonTimeout, Message: "query timed out", Err: err}
+	}
+
+	return models.NewInternal(err)
+}
+
+// ReferencedEntity names the entity missing behind a foreign key or not-null
+// violation, judging by the column (author, forum, thread, user_nick...) or,
+// for foreign keys, by the default constraint name <table>_<column>_fkey.
+func ReferencedEntity(pgErr pgx.PgError) string {
+	column := pgErr.ColumnName
+	if column == "" {
+		column = strings.TrimPrefix(pgErr.ConstraintName, pgErr.TableName+"_")
+	}
+
+	switch {
+	case strings.Contains(column, "forum"):
+		return models.EntityForum
+	case strings.Contains(column, "thread"):
+		return models.EntityThread
+	case strings.Contains(column, "parent"):
+		return models.EntityPost
+	default:
+		return models.EntityUser
+	}
+}
+
+// logError logs a failed query with the logger of the request. Constraint
+// violations are part of normal operation (conflicts, unknown authors) and
+// are only logged at debug level.
+func (db *DB) logError(ctx context.Context, method string, sql string, err error) {
+	if err == nil || err == pgx.ErrNoRows {
+		return
+	}
+
+	level := logger.LevelError
+	keyvals := []interface{}{"method", method, "sql", compactSQL(sql), "error", err}
+	if pgErr, ok := err.(pgx.PgError); ok {
+		keyvals = append(keyvals, "code", pgErr.Code)
+		if strings.HasPrefix(pgErr.Code, "23") || pgErr.Code == "00409" {
+			level = logger.LevelDebug
+		}
+	}
+	if err == context.Canceled || err == context.DeadlineExceeded || err == pgx.ErrAcquireTimeout {
+		level = logger.LevelWarn
+	}
+
+	db.log.For(ctx).Log(level, "query failed", keyvals...)
+}
+
+const maxLoggedSQL = 512
+
+func compactSQL(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+	if len(sql) > maxLoggedSQL {
+		sql = sql[:maxLoggedSQL] + "..."
+	}
+	return sql
+}
@@ -0,0 +1,78 @@
+package dbConn
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EgorAist/TP_DB_project/internal/metrics"
+	"github.com/jackc/pgx"
+)
+
+var (
+	queryDuration = metrics.NewHistogramVec("storage_query_duration_seconds",
+		"Time from issuing a query until its connection is released, by storage method.",
+		metrics.DefaultBuckets, "method")
+	acquireDuration = metrics.NewHistogramVec("db_pool_acquire_duration_seconds",
+		"Time spent waiting for a pool connection.", metrics.DefaultBuckets)
+)
+
+func registerPoolMetrics(pool *pgx.ConnPool) {
+	stat := func(value func(s pgx.ConnPoolStat) int) func() float64 {
+		return func() float64 {
+			return float64(value(pool.Stat()))
+		}
+	}
+
+	metrics.NewGaugeFunc("db_pool_max_connections", "Size of the connection pool.",
+		stat(func(s pgx.ConnPoolStat) int { return s.MaxConnections }))
+	metrics.NewGaugeFunc("db_pool_connections", "Open connections.",
+		stat(func(s pgx.ConnPoolStat) int { return s.CurrentConnections }))
+	metrics.NewGaugeFunc("db_pool_available_connections", "Open connections not in use.",
+		stat(func(s pgx.ConnPoolStat) int { return s.AvailableConnections }))
+	metrics.NewGaugeFunc("db_pool_acquired_connections", "Connections in use.",
+		stat(func(s pgx.ConnPoolStat) int { return s.CheckedOutConnections() }))
+}
+
+// observe starts timing a query of method; call the result when the
+// connection is released. Queries slower than the configured threshold are
+// passed on to the slow query log.
+func (db *DB) observe(ctx context.Context, method string, sql string, args []interface{}) func() {
+	started := time.Now()
+	return func() {
+		duration := time.Since(started)
+		queryDuration.Observe(duration.Seconds(), method)
+		if db.slow.Threshold > 0 && duration >= db.slow.Threshold {
+			db.slowQuery(ctx, method, sql, args, duration)
+		}
+	}
+}
+
+var (
+	methodNames sync.Map
+	receiver    = strings.NewReplacer("(*storage).", "", ".storage.", ".", "(*service).", "", ".service.", ".")
+)
+
+// callerMethod names the storage method that called into DB, e.g.
+// forumStorage.CreateForum. It must be called directly from a DB method.
+func callerMethod() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown"
+	}
+
+	if name, ok := methodNames.Load(pc); ok {
+		return name.(string)
+	}
+
+	name := "unknown"
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		name = fn.Name()
+		name = name[strings.LastIndex(name, "/")+1:]
+		name = receiver.Replace(name)
+	}
+	methodNames.Store(pc, name)
+	return name
+}
@@ -0,0 +1,204 @@
+package dbConn
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/jackc/pgx"
+)
+
+const (
+	// maxPlans is how many slow queries the plan store keeps.
+	maxPlans = 100
+
+	explainTimeout = 10 * time.Second
+	maxLoggedArg   = 128
+)
+
+// SlowQueryConfig configures the slow query log. A zero Threshold disables
+// it; ExplainRate is the share (0 to 1) of slow SELECT queries that are run
+// again under EXPLAIN (ANALYZE, BUFFERS) to capture their plan.
+type SlowQueryConfig struct {
+	Threshold   time.Duration
+	ExplainRate float64
+}
+
+var (
+	queryNamesMu sync.RWMutex
+	queryNames   = make(map[string]string)
+)
+
+// RegisterQueries names the SQL statements of a storage, keyed by name, so
+// that the slow query log reports selectPostsTreeLimitByID rather than the
+// whole statement. Storages call it from init.
+func RegisterQueries(queries map[string]string) {
+	queryNamesMu.Lock()
+	defer queryNamesMu.Unlock()
+
+	for name, sql := range queries {
+		queryNames[sql] = name
+	}
+}
+
+func queryName(sql string) string {
+	queryNamesMu.RLock()
+	defer queryNamesMu.RUnlock()
+
+	return queryNames[sql]
+}
+
+// slowQuery logs a query that exceeded the threshold and records it in the
+// plan store, with its plan when it is sampled for EXPLAIN.
+func (db *DB) slowQuery(ctx context.Context, method string, sql string, args []interface{}, duration time.Duration) {
+	query := models.SlowQuery{
+		Name:       queryName(sql),
+		Method:     method,
+		SQL:        compactSQL(sql),
+		Args:       formatArgs(args),
+		DurationMs: float64(duration) / float64(time.Millisecond),
+		CapturedAt: time.Now(),
+	}
+
+	db.log.For(ctx).Warn("slow query",
+		"name", query.Name,
+		"method", method,
+		"sql", query.SQL,
+		"args", query.Args,
+		"duration", duration,
+	)
+
+	if db.slow.ExplainRate > 0 && explainable(sql) && rand.Float64() < db.slow.ExplainRate {
+		go db.explain(query, sql, args)
+		return
+	}
+	db.plans.add(query)
+}
+
+// explaining allows a single EXPLAIN at a time, so that a burst of slow
+// queries does not double the load that made them slow.
+var explaining int32
+
+// explain runs sql again under EXPLAIN (ANALYZE, BUFFERS) in a read-only
+// transaction that is rolled back, and stores the plan.
+func (db *DB) explain(query models.SlowQuery, sql string, args []interface{}) {
+	if !atomic.CompareAndSwapInt32(&explaining, 0, 1) {
+		db.plans.add(query)
+		return
+	}
+	defer atomic.StoreInt32(&explaining, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+	defer cancel()
+
+	plan, err := db.queryPlan(ctx, sql, args)
+	if err != nil {
+		db.log.Warn("explain failed", "name", query.Name, "method", query.Method, "error", err)
+		query.Plan = "explain failed: " + err.Error()
+	} else {
+		query.Plan = plan
+	}
+	db.plans.add(query)
+}
+
+func (db *DB) queryPlan(ctx context.Context, sql string, args []interface{}) (string, error) {
+	tx, err := db.pool.BeginEx(ctx, &pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryEx(ctx, "EXPLAIN (ANALYZE, BUFFERS) "+sql, nil, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err = rows.Scan(&line); err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// explainable reports whether sql only reads, so that running it again
+// under EXPLAIN ANALYZE has no side effects.
+func explainable(sql string) bool {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "WITH":
+		return true
+	}
+	return false
+}
+
+func formatArgs(args []interface{}) []string {
+	formatted := make([]string, 0, len(args))
+	for _, arg := range args {
+		s := fmt.Sprint(arg)
+		if len(s) > maxLoggedArg {
+			s = s[:maxLoggedArg] + "..."
+		}
+		formatted = append(formatted, s)
+	}
+	return formatted
+}
+
+// PlanStore keeps the most recent slow queries.
+type PlanStore struct {
+	mu      sync.Mutex
+	queries []models.SlowQuery
+	next    int
+	size    int
+}
+
+func newPlanStore(size int) *PlanStore {
+	return &PlanStore{
+		queries: make([]models.SlowQuery, size),
+	}
+}
+
+func (p *PlanStore) add(query models.SlowQuery) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.queries[p.next] = query
+	p.next = (p.next + 1) % len(p.queries)
+	if p.size < len(p.queries) {
+		p.size++
+	}
+}
+
+// recent returns the stored queries, newest first.
+func (p *PlanStore) recent() []models.SlowQuery {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	queries := make([]models.SlowQuery, 0, p.size)
+	for i := 1; i <= p.size; i++ {
+		queries = append(queries, p.queries[(p.next-i+len(p.queries))%len(p.queries)])
+	}
+	return queries
+}
+
+// SlowQueries returns the most recent slow queries, newest first.
+func (db *DB) SlowQueries() []models.SlowQuery {
+	return db.plans.recent()
+}
@@ -1,79 +1,104 @@
 package forumStorage
 
 import (
+	"context"
 	"github.com/jackc/pgerrcode"
+	"github.com/EgorAist/TP_DB_project/internal/storages/dbConn"
 	"github.com/jackc/pgx"
 	"github.com/EgorAist/TP_DB_project/internal/models"
 )
 
 type Storage interface {
-	CreateForum(forumSlug models.ForumCreate) (forum models.Forum, err error)
-	GetDetails(forumSlug models.ForumInput) (forum models.Forum, err error)
-	UpdateThreadsCount(input models.ForumInput) (err error)
-	UpdatePostsCount(input models.ForumInput, posts int) (err error)
+	CreateForum(ctx context.Context, forumSlug models.ForumCreate) (forum models.Forum, err error)
+	GetDetails(ctx context.Context, forumSlug models.ForumInput) (forum models.Forum, err error)
+	UpdateThreadsCount(ctx context.Context, input models.ForumInput, threads int) (err error)
+	UpdatePostsCount(ctx context.Context, input models.ForumInput, posts int) (err error)
 //	AddUserToForum(userID int, forumID int) (err error)
-	GetForumSlug(slug string) (string, error)
-	AddUserToForum(user string, forum string) (err error)
-	CheckIfForumExists(input models.ForumInput) (err error)
-	GetForumID(input models.ForumInput) (ID int, err error)
-	GetForumForPost(forumSlug string, forum *models.Forum) (err error)
+	GetForumSlug(ctx context.Context, slug string) (string, error)
+	AddUserToForum(ctx context.Context, user string, forum string) (err error)
+	RemoveThreadUsers(ctx context.Context, forum string, thread int) (err error)
+	RemoveUser(ctx context.Context, nickname string) (err error)
+	GetForumsByOwner(ctx context.Context, nickname string) (forums []models.Forum, err error)
+	GetMemberships(ctx context.Context, nickname string) (forums []string, err error)
+	UpdateForum(ctx context.Context, input models.ForumUpdate) (forum models.Forum, err error)
+	ResolveAlias(ctx context.Context, slug string) (forum string, err error)
+	AddAlias(ctx context.Context, alias string, forum string) (err error)
+	RemoveAlias(ctx context.Context, alias string) (err error)
+	CountForumContents(ctx context.Context, input models.ForumInput) (deletion models.ForumDeletion, err error)
+	DeleteForum(ctx context.Context, input models.ForumInput) (deletion models.ForumDeletion, err error)
+	CheckIfForumExists(ctx context.Context, input models.ForumInput) (err error)
+	GetForumID(ctx context.Context, input models.ForumInput) (ID int, err error)
+	GetForumForPost(ctx context.Context, forumSlug string, forum *models.Forum) (err error)
+	GetChildren(ctx context.Context, input models.ForumInput) (forums []models.Forum, err error)
+	GetSubtree(ctx context.Context, input models.ForumInput) (forums []string, err error)
+	GetTotals(ctx context.Context, input models.ForumInput) (totals models.ForumTotals, err error)
 }
 
 type storage struct {
-	db *pgx.ConnPool
+	db *dbConn.DB
 }
 
-func NewStorage(db *pgx.ConnPool) Storage {
+func NewStorage(db *dbConn.DB) Storage {
 	return &storage{
 		db: db,
 	}
 }
 
-func (s *storage) CreateForum(forumSlug models.ForumCreate) (forum models.Forum, err error) {
-	err = s.db.QueryRow("INSERT INTO forums (slug, title, user_nick) VALUES ($1, $2,(SELECT u.nickname FROM users u WHERE u.nickname = $3)) RETURNING slug, title, user_nick",
-						forumSlug.Slug, forumSlug.Title, forumSlug.User).Scan(&forum.Slug, &forum.Title, &forum.User)
+// parentFkey is the constraint that ties a forum to its parent; the other
+// foreign key of forums is the owner.
+const parentFkey = "forums_parent_fkey"
+
+func (s *storage) CreateForum(ctx context.Context, forumSlug models.ForumCreate) (forum models.Forum, err error) {
+	err = s.db.QueryRow(ctx, "INSERT INTO forums (slug, title, user_nick, parent) VALUES ($1, $2,(SELECT u.nickname FROM users u WHERE u.nickname = $3), NULLIF($4::TEXT, '')::CITEXT) RETURNING slug, title, user_nick, coalesce(parent, '')",
+						forumSlug.Slug, forumSlug.Title, forumSlug.User, forumSlug.Parent).Scan(&forum.Slug, &forum.Title, &forum.User, &forum.Parent)
 
 	if pqErr, ok := err.(pgx.PgError); ok {
-		switch pqErr.Code {
-		case pgerrcode.UniqueViolation:
-			return forum, models.Error{Code: "409"}
-		case pgerrcode.NotNullViolation, pgerrcode.ForeignKeyViolation:
-			return forum, models.Error{Code: "404"}
+		switch {
+		case pqErr.Code == pgerrcode.UniqueViolation:
+			return forum, models.NewConflict(models.EntityForum, models.ReasonAlreadyExists, "forum already exists")
+		case pqErr.Code == pgerrcode.ForeignKeyViolation && pqErr.ConstraintName == parentFkey:
+			return forum, models.NewNotFound(models.EntityForum)
+		case pqErr.Code == pgerrcode.NotNullViolation, pqErr.Code == pgerrcode.ForeignKeyViolation:
+			return forum, models.NewNotFound(models.EntityUser)
 		default:
-			return forum, models.Error{Code: "500"}
+			return forum, dbConn.InternalError(err)
 		}
 	}
 
+	if err != nil {
+		return forum, dbConn.InternalError(err)
+	}
+
 	return forum, nil
 }
 
-func (s *storage) GetDetails(forumSlug models.ForumInput) (forum models.Forum, err error) {
-	err = s.db.QueryRow("SELECT slug, title, threads, posts, user_nick FROM forums WHERE slug = $1", forumSlug.Slug).
-				Scan(&forum.Slug, &forum.Title, &forum.Threads, &forum.Posts, &forum.User)
+func (s *storage) GetDetails(ctx context.Context, forumSlug models.ForumInput) (forum models.Forum, err error) {
+	err = s.db.QueryRow(ctx, "SELECT slug, title, threads, posts, user_nick, coalesce(parent, '') FROM forums WHERE slug = $1", forumSlug.Slug).
+				Scan(&forum.Slug, &forum.Title, &forum.Threads, &forum.Posts, &forum.User, &forum.Parent)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return forum, models.Error{Code: "404"}
+			return forum, models.NewNotFound(models.EntityForum)
 
 		}
-		return forum, models.Error{Code: "500"}
+		return forum, dbConn.InternalError(err)
 	}
 
 	return forum, nil
 }
 
-func (s *storage) UpdateThreadsCount(input models.ForumInput) (err error) {
-	_, err = s.db.Exec("UPDATE forums SET threads = threads + 1 WHERE slug = $1", input.Slug)
+func (s *storage) UpdateThreadsCount(ctx context.Context, input models.ForumInput, threads int) (err error) {
+	_, err = s.db.Exec(ctx, "UPDATE forums SET threads = threads + $2 WHERE slug = $1", input.Slug, threads)
 	if err != nil {
-		return models.Error{Code: "500"}
+		return dbConn.InternalError(err)
 	}
 	return
 }
 
-func (s *storage) UpdatePostsCount(input models.ForumInput, posts int) (err error) {
-	_, err = s.db.Exec("UPDATE forums SET posts = posts + $2 WHERE slug = $1", input.Slug, posts)
+func (s *storage) UpdatePostsCount(ctx context.Context, input models.ForumInput, posts int) (err error) {
+	_, err = s.db.Exec(ctx, "UPDATE forums SET posts = posts + $2 WHERE slug = $1", input.Slug, posts)
 	if err != nil {
-		return models.Error{Code: "500"}
+		return dbConn.InternalError(err)
 	}
 	return
 }
@@ -94,67 +119,366 @@ func (s *storage) AddUserToForum(userID int, forumID int) (err error) {
 }
 */
 
-func (s *storage) AddUserToForum(user string, forum string) (err error) {
-	_, err = s.db.Exec("INSERT INTO forum_users (forum, nickname) VALUES ($1, $2)", forum, user)
+// AddUserToForum ignores users that are already listed, so that it never
+// aborts the transaction it runs in.
+func (s *storage) AddUserToForum(ctx context.Context, user string, forum string) (err error) {
+	_, err = s.db.Exec(ctx, "INSERT INTO forum_users (forum, nickname) VALUES ($1, $2) ON CONFLICT DO NOTHING", forum, user)
 	if err != nil {
-		if pqErr, ok := err.(pgx.PgError); ok {
-			switch pqErr.Code {
-			case pgerrcode.UniqueViolation:
-				return models.Error{Code: "409"}
-			}
-		}
-		return models.Error{Code: "500"}
+		return dbConn.InternalError(err)
 	}
 
 	return
 }
 
 
-func (s *storage) CheckIfForumExists(input models.ForumInput) (err error) {
+func (s *storage) CheckIfForumExists(ctx context.Context, input models.ForumInput) (err error) {
 	var ID int
-	err = s.db.QueryRow("SELECT ID from forums WHERE slug = $1", input.Slug).Scan(&ID)
+	err = s.db.QueryRow(ctx, "SELECT ID from forums WHERE slug = $1", input.Slug).Scan(&ID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return models.Error{Code: "404"}
+			return models.NewNotFound(models.EntityForum)
 		}
-		return models.Error{Code: "500"}
+		return dbConn.InternalError(err)
 	}
 
 	return
 }
 
-func (s storage) GetForumID(input models.ForumInput) (ID int, err error) {
-	err = s.db.QueryRow("SELECT ID from forums WHERE slug = $1", input.Slug).Scan(&ID)
+func (s storage) GetForumID(ctx context.Context, input models.ForumInput) (ID int, err error) {
+	err = s.db.QueryRow(ctx, "SELECT ID from forums WHERE slug = $1", input.Slug).Scan(&ID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return ID, models.Error{Code: "404"}
+			return ID, models.NewNotFound(models.EntityForum)
 		}
-		return ID, models.Error{Code: "500"}
+		return ID, dbConn.InternalError(err)
 	}
 
 	return
 }
-func (s storage) GetForumSlug(slug string) (string, error) {
+func (s storage) GetForumSlug(ctx context.Context, slug string) (string, error) {
 	var rightSlug string
-	err := s.db.QueryRow("SELECT slug from forums WHERE slug = $1", slug).Scan(&rightSlug)
+	err := s.db.QueryRow(ctx, "SELECT slug from forums WHERE slug = $1", slug).Scan(&rightSlug)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return rightSlug, models.Error{Code: "404"}
+			return rightSlug, models.NewNotFound(models.EntityForum)
 		}
-		return rightSlug, models.Error{Code: "500"}
+		return rightSlug, dbConn.InternalError(err)
 	}
 
 	return rightSlug, nil
 }
 
-func (s *storage) GetForumForPost(forumSlug string, forum *models.Forum) (err error) {
+func (s *storage) GetForumForPost(ctx context.Context, forumSlug string, forum *models.Forum) (err error) {
 	forum.Slug = forumSlug
-	err = s.db.QueryRow("SELECT title, threads, posts, user_nick FROM forums WHERE slug = $1", forumSlug).
-		Scan(&forum.Title, &forum.Threads, &forum.Posts, &forum.User)
+	err = s.db.QueryRow(ctx, "SELECT title, threads, posts, user_nick, coalesce(parent, '') FROM forums WHERE slug = $1", forumSlug).
+		Scan(&forum.Title, &forum.Threads, &forum.Posts, &forum.User, &forum.Parent)
 
 	if err != nil {
-		return models.Error{Code: "500"}
+		return dbConn.InternalError(err)
+	}
+
+	return
+}
+
+const removeThreadUsers = `
+	DELETE FROM forum_users fu
+	WHERE fu.forum = $1
+	  AND fu.nickname IN (
+		SELECT t.author FROM threads t WHERE t.ID = $2
+		UNION
+		SELECT p.author FROM posts p WHERE p.thread = $2
+	  )
+	  AND NOT EXISTS (
+		SELECT 1 FROM threads t WHERE t.forum = $1 AND t.author = fu.nickname AND t.state <> 'deleted'
+	  )
+	  AND NOT EXISTS (
+		SELECT 1 FROM posts p JOIN threads t ON t.ID = p.thread
+		WHERE p.forum = $1 AND p.author = fu.nickname AND t.state <> 'deleted'
+	  )
+`
+
+// RemoveThreadUsers drops from the forum the participants of a deleted thread
+// who have no other thread or post left in it.
+func (s *storage) RemoveThreadUsers(ctx context.Context, forum string, thread int) (err error) {
+	_, err = s.db.Exec(ctx, removeThreadUsers, forum, thread)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+
+	return
+}
+
+const updateForum = `
+	UPDATE forums SET
+		title = COALESCE(NULLIF($2::TEXT, ''), title),
+		user_nick = CASE WHEN $3::TEXT = '' THEN user_nick ELSE (SELECT u.nickname FROM users u WHERE u.nickname = $3::CITEXT) END,
+		slug = COALESCE(NULLIF($4::CITEXT, ''), slug),
+		parent = CASE WHEN $5::BOOLEAN THEN NULLIF($6::TEXT, '')::CITEXT ELSE parent END
+	WHERE slug = $1
+	RETURNING slug, title, threads, posts, user_nick, coalesce(parent, '')
+`
+
+// UpdateForum changes the fields of input that are set. A new slug is carried
+// over to threads, posts, forum_users and subforums by their foreign keys.
+func (s *storage) UpdateForum(ctx context.Context, input models.ForumUpdate) (forum models.Forum, err error) {
+	var parent string
+	if input.Parent != nil {
+		parent = *input.Parent
+	}
+	err = s.db.QueryRow(ctx, updateForum, input.Slug, input.Title, input.User, input.NewSlug, input.Parent != nil, parent).
+				Scan(&forum.Slug, &forum.Title, &forum.Threads, &forum.Posts, &forum.User, &forum.Parent)
+
+	if pqErr, ok := err.(pgx.PgError); ok {
+		switch {
+		case pqErr.Code == pgerrcode.UniqueViolation:
+			return forum, models.NewConflict(models.EntityForum, models.ReasonAlreadyExists, "forum already exists")
+		case pqErr.Code == pgerrcode.ForeignKeyViolation && pqErr.ConstraintName == parentFkey:
+			return forum, models.NewNotFound(models.EntityForum)
+		case pqErr.Code == pgerrcode.NotNullViolation, pqErr.Code == pgerrcode.ForeignKeyViolation:
+			return forum, models.NewNotFound(models.EntityUser)
+		default:
+			return forum, dbConn.InternalError(err)
+		}
+	}
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return forum, models.NewNotFound(models.EntityForum)
+		}
+		return forum, dbConn.InternalError(err)
+	}
+
+	return forum, nil
+}
+
+// ResolveAlias returns the current slug of the forum that was once called slug.
+func (s *storage) ResolveAlias(ctx context.Context, slug string) (forum string, err error) {
+	err = s.db.QueryRow(ctx, "SELECT forum FROM forum_aliases WHERE slug = $1", slug).Scan(&forum)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return forum, models.NewNotFound(models.EntityForum)
+		}
+		return forum, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) AddAlias(ctx context.Context, alias string, forum string) (err error) {
+	_, err = s.db.Exec(ctx, "INSERT INTO forum_aliases (slug, forum) VALUES ($1, $2) ON CONFLICT (slug) DO UPDATE SET forum = EXCLUDED.forum, created = now()", alias, forum)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) RemoveAlias(ctx context.Context, alias string) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM forum_aliases WHERE slug = $1", alias)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+
+	return
+}
+
+const countForumContents = `
+	SELECT f.slug,
+		(SELECT count(*) FROM threads t WHERE t.forum = f.slug),
+		(SELECT count(*) FROM posts p JOIN threads t ON t.ID = p.thread WHERE t.forum = f.slug),
+		(SELECT count(*) FROM votes v JOIN threads t ON t.ID = v.thread WHERE t.forum = f.slug),
+		(SELECT count(*) FROM post_votes pv JOIN posts p ON p.ID = pv.post WHERE p.forum = f.slug),
+		(SELECT count(*) FROM forum_users fu WHERE fu.forum = f.slug),
+		(SELECT count(*) FROM forum_aliases a WHERE a.forum = f.slug)
+	FROM forums f
+	WHERE f.slug = $1
+`
+
+// CountForumContents reports what DeleteForum would remove.
+func (s *storage) CountForumContents(ctx context.Context, input models.ForumInput) (deletion models.ForumDeletion, err error) {
+	err = s.db.QueryRow(ctx, countForumContents, input.Slug).
+				Scan(&deletion.Forum, &deletion.Threads, &deletion.Posts, &deletion.Votes, &deletion.PostVotes, &deletion.Users, &deletion.Aliases)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return deletion, models.NewNotFound(models.EntityForum)
+		}
+		return deletion, dbConn.InternalError(err)
+	}
+
+	return deletion, s.checkNoChildren(ctx, deletion.Forum)
+}
+
+// checkNoChildren refuses to delete a forum that still has subforums; they
+// have to be moved or deleted first.
+func (s *storage) checkNoChildren(ctx context.Context, slug string) error {
+	var children bool
+	err := s.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM forums WHERE parent = $1)", slug).Scan(&children)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	if children {
+		return models.NewConflict(models.EntityForum, models.ReasonHasChildren, "forum has subforums")
+	}
+	return nil
+}
+
+// DeleteForum removes the forum with everything in it. It has to run in a
+// transaction: the forum row stays locked until the end so that no thread can
+// be created in it meanwhile.
+func (s *storage) DeleteForum(ctx context.Context, input models.ForumInput) (deletion models.ForumDeletion, err error) {
+	err = s.db.QueryRow(ctx, "SELECT slug FROM forums WHERE slug = $1 FOR UPDATE", input.Slug).Scan(&deletion.Forum)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return deletion, models.NewNotFound(models.EntityForum)
+		}
+		return deletion, dbConn.InternalError(err)
+	}
+	if err = s.checkNoChildren(ctx, deletion.Forum); err != nil {
+		return deletion, err
+	}
+
+	steps := []struct {
+		query string
+		count *int
+	}{
+		{"DELETE FROM votes v USING threads t WHERE t.ID = v.thread AND t.forum = $1", &deletion.Votes},
+		{"DELETE FROM post_votes pv USING posts p WHERE p.ID = pv.post AND p.forum = $1", &deletion.PostVotes},
+		{"DELETE FROM posts p USING threads t WHERE t.ID = p.thread AND t.forum = $1", &deletion.Posts},
+		{"DELETE FROM threads WHERE forum = $1", &deletion.Threads},
+		{"DELETE FROM forum_users WHERE forum = $1", &deletion.Users},
+		{"DELETE FROM forum_aliases WHERE forum = $1", &deletion.Aliases},
+		{"DELETE FROM forums WHERE slug = $1", nil},
+	}
+	for _, step := range steps {
+		tag, err := s.db.Exec(ctx, step.query, deletion.Forum)
+		if err != nil {
+			return deletion, dbConn.InternalError(err)
+		}
+		if step.count != nil {
+			*step.count = int(tag.RowsAffected())
+		}
+	}
+
+	return deletion, nil
+}
+
+// RemoveUser takes the user off the user lists of every forum.
+func (s *storage) RemoveUser(ctx context.Context, nickname string) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM forum_users WHERE nickname = $1", nickname)
+	if err != nil {
+		return dbConn.InternalError(err)
 	}
 
 	return
-}
\ No newline at end of file
+}
+
+func (s *storage) GetForumsByOwner(ctx context.Context, nickname string) (forums []models.Forum, err error) {
+	rows, err := s.db.Query(ctx, "SELECT slug, title, threads, posts, user_nick, coalesce(parent, '') FROM forums WHERE user_nick = $1 ORDER BY slug", nickname)
+	if err != nil {
+		return forums, dbConn.InternalError(err)
+	}
+	return scanForums(rows)
+}
+
+// GetChildren lists the direct subforums of the forum.
+func (s *storage) GetChildren(ctx context.Context, input models.ForumInput) (forums []models.Forum, err error) {
+	rows, err := s.db.Query(ctx, "SELECT slug, title, threads, posts, user_nick, coalesce(parent, '') FROM forums WHERE parent = $1 ORDER BY slug", input.Slug)
+	if err != nil {
+		return forums, dbConn.InternalError(err)
+	}
+	return scanForums(rows)
+}
+
+func scanForums(rows *dbConn.Rows) (forums []models.Forum, err error) {
+	defer rows.Close()
+
+	forums = make([]models.Forum, 0)
+	for rows.Next() {
+		forum := models.Forum{}
+		if err = rows.Scan(&forum.Slug, &forum.Title, &forum.Threads, &forum.Posts, &forum.User, &forum.Parent); err != nil {
+			return forums, dbConn.InternalError(err)
+		}
+		forums = append(forums, forum)
+	}
+
+	if err = rows.Err(); err != nil {
+		return forums, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// forumTree selects the slugs of the forum and of all its descendants. UNION
+// rather than UNION ALL stops the recursion should a cycle ever appear.
+const forumTree = `
+	WITH RECURSIVE tree (slug) AS (
+		SELECT slug FROM forums WHERE slug = $1
+		UNION
+		SELECT f.slug FROM forums f JOIN tree t ON f.parent = t.slug
+	)`
+
+const (
+	selectSubtree = forumTree + `
+	SELECT slug FROM tree`
+	selectTotals = forumTree + `
+	SELECT coalesce(sum(f.threads), 0)::INTEGER, coalesce(sum(f.posts), 0)::INTEGER, (count(*) - 1)::INTEGER
+	FROM forums f JOIN tree t ON t.slug = f.slug`
+)
+
+// GetSubtree returns the slugs of the forum and of all its descendants; it is
+// empty for a missing forum.
+func (s *storage) GetSubtree(ctx context.Context, input models.ForumInput) (forums []string, err error) {
+	rows, err := s.db.Query(ctx, selectSubtree, input.Slug)
+	if err != nil {
+		return forums, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	forums = make([]string, 0)
+	for rows.Next() {
+		var forum string
+		if err = rows.Scan(&forum); err != nil {
+			return forums, dbConn.InternalError(err)
+		}
+		forums = append(forums, forum)
+	}
+
+	if err = rows.Err(); err != nil {
+		return forums, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// GetTotals adds up the counters of the forum and all its descendants.
+func (s *storage) GetTotals(ctx context.Context, input models.ForumInput) (totals models.ForumTotals, err error) {
+	err = s.db.QueryRow(ctx, selectTotals, input.Slug).Scan(&totals.Threads, &totals.Posts, &totals.Subforums)
+	if err != nil {
+		return totals, dbConn.InternalError(err)
+	}
+	return
+}
+
+// GetMemberships lists the slugs of the forums the user has posted in.
+func (s *storage) GetMemberships(ctx context.Context, nickname string) (forums []string, err error) {
+	rows, err := s.db.Query(ctx, "SELECT forum FROM forum_users WHERE nickname = $1 ORDER BY forum", nickname)
+	if err != nil {
+		return forums, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	forums = make([]string, 0)
+	for rows.Next() {
+		var forum string
+		if err = rows.Scan(&forum); err != nil {
+			return forums, dbConn.InternalError(err)
+		}
+		forums = append(forums, forum)
+	}
+
+	if err = rows.Err(); err != nil {
+		return forums, dbConn.InternalError(err)
+	}
+
+	return
+}
@@ -0,0 +1,208 @@
+package mentionStorage
+
+import (
+	"context"
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/EgorAist/TP_DB_project/internal/storages/dbConn"
+)
+
+type Storage interface {
+	ResolveNicknames(ctx context.Context, nicknames []string) (existing []string, err error)
+	ReplaceThreadMentions(ctx context.Context, thread models.Thread, nicknames []string) (err error)
+	ReplacePostMentions(ctx context.Context, post models.Post, nicknames []string) (err error)
+
+	GetMentions(ctx context.Context, input models.UserGetMentions) (mentions []models.Mention, err error)
+	GetUserMentions(ctx context.Context, nickname string) (mentions []models.Mention, err error)
+	DeleteUser(ctx context.Context, nickname string) (err error)
+	DeleteAuthor(ctx context.Context, nickname string) (err error)
+}
+
+type storage struct {
+	db *dbConn.DB
+}
+
+func NewStorage(db *dbConn.DB) Storage {
+	return &storage{
+		db: db,
+	}
+}
+
+func init() {
+	dbConn.RegisterQueries(map[string]string{
+		"selectMentions":          selectMentions,
+		"selectMentionsSince":     selectMentionsSince,
+		"selectMentionsDesc":      selectMentionsDesc,
+		"selectMentionsSinceDesc": selectMentionsSinceDesc,
+	})
+}
+
+// ResolveNicknames returns the nicknames, as stored, of the users among the
+// given ones. Nicknames are compared ignoring case.
+func (s *storage) ResolveNicknames(ctx context.Context, nicknames []string) (existing []string, err error) {
+	existing = make([]string, 0, len(nicknames))
+	if len(nicknames) == 0 {
+		return
+	}
+
+	rows, err := s.db.Query(ctx, "SELECT nickname FROM users WHERE nickname = ANY($1::TEXT[]::CITEXT[])", nicknames)
+	if err != nil {
+		return existing, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var nickname string
+		if err = rows.Scan(&nickname); err != nil {
+			return existing, dbConn.InternalError(err)
+		}
+		existing = append(existing, nickname)
+	}
+
+	if err = rows.Err(); err != nil {
+		return existing, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// ReplaceThreadMentions makes the given users the ones mentioned by the
+// message of the thread. Nicknames of missing users are skipped.
+func (s *storage) ReplaceThreadMentions(ctx context.Context, thread models.Thread, nicknames []string) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM mentions WHERE thread = $1 AND post IS NULL", thread.ID)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	if len(nicknames) == 0 {
+		return
+	}
+
+	_, err = s.db.Exec(ctx, "INSERT INTO mentions (user_nick, thread, author) "+
+		"SELECT nickname, $2, $3 FROM users WHERE nickname = ANY($1::TEXT[]::CITEXT[])",
+		nicknames, thread.ID, thread.Author)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
+
+// ReplacePostMentions makes the given users the ones mentioned by the post.
+// Nicknames of missing users are skipped.
+func (s *storage) ReplacePostMentions(ctx context.Context, post models.Post, nicknames []string) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM mentions WHERE post = $1", post.ID)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	if len(nicknames) == 0 {
+		return
+	}
+
+	_, err = s.db.Exec(ctx, "INSERT INTO mentions (user_nick, thread, post, author) "+
+		"SELECT nickname, $2, $3, $4 FROM users WHERE nickname = ANY($1::TEXT[]::CITEXT[])",
+		nicknames, post.ThreadInput.ThreadID, post.ID, post.Author)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
+
+// Mentions in deleted threads are left out; since is the ID the page starts
+// after.
+const (
+	selectMentions = `
+	SELECT m.ID, t.forum, m.thread, coalesce(m.post, 0), m.author, m.created
+	FROM mentions m JOIN threads t ON t.ID = m.thread
+	WHERE m.user_nick = $1 AND t.state <> 'deleted'
+	ORDER BY m.ID LIMIT $2`
+	selectMentionsSince = `
+	SELECT m.ID, t.forum, m.thread, coalesce(m.post, 0), m.author, m.created
+	FROM mentions m JOIN threads t ON t.ID = m.thread
+	WHERE m.user_nick = $1 AND t.state <> 'deleted' AND m.ID > $2
+	ORDER BY m.ID LIMIT $3`
+	selectMentionsDesc = `
+	SELECT m.ID, t.forum, m.thread, coalesce(m.post, 0), m.author, m.created
+	FROM mentions m JOIN threads t ON t.ID = m.thread
+	WHERE m.user_nick = $1 AND t.state <> 'deleted'
+	ORDER BY m.ID DESC LIMIT $2`
+	selectMentionsSinceDesc = `
+	SELECT m.ID, t.forum, m.thread, coalesce(m.post, 0), m.author, m.created
+	FROM mentions m JOIN threads t ON t.ID = m.thread
+	WHERE m.user_nick = $1 AND t.state <> 'deleted' AND m.ID < $2
+	ORDER BY m.ID DESC LIMIT $3`
+)
+
+func (s *storage) GetMentions(ctx context.Context, input models.UserGetMentions) (mentions []models.Mention, err error) {
+	var rows *dbConn.Rows
+	if input.Since == 0 && !input.Desc {
+		rows, err = s.db.Query(ctx, selectMentions, input.Nickname, input.Limit)
+	} else if input.Since == 0 && input.Desc {
+		rows, err = s.db.Query(ctx, selectMentionsDesc, input.Nickname, input.Limit)
+	} else if input.Since != 0 && !input.Desc {
+		rows, err = s.db.Query(ctx, selectMentionsSince, input.Nickname, input.Since, input.Limit)
+	} else {
+		rows, err = s.db.Query(ctx, selectMentionsSinceDesc, input.Nickname, input.Since, input.Limit)
+	}
+	if err != nil {
+		return mentions, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	mentions = make([]models.Mention, 0)
+	for rows.Next() {
+		mention := models.Mention{}
+		err = rows.Scan(&mention.ID, &mention.Forum, &mention.Thread, &mention.Post, &mention.Author, &mention.Created)
+		if err != nil {
+			return mentions, dbConn.InternalError(err)
+		}
+		mentions = append(mentions, mention)
+	}
+
+	if err = rows.Err(); err != nil {
+		return mentions, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// GetUserMentions lists every mention of the user, deleted threads included.
+func (s *storage) GetUserMentions(ctx context.Context, nickname string) (mentions []models.Mention, err error) {
+	rows, err := s.db.Query(ctx, "SELECT m.ID, t.forum, m.thread, coalesce(m.post, 0), m.author, m.created "+
+		"FROM mentions m JOIN threads t ON t.ID = m.thread WHERE m.user_nick = $1 ORDER BY m.ID", nickname)
+	if err != nil {
+		return mentions, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	mentions = make([]models.Mention, 0)
+	for rows.Next() {
+		mention := models.Mention{}
+		err = rows.Scan(&mention.ID, &mention.Forum, &mention.Thread, &mention.Post, &mention.Author, &mention.Created)
+		if err != nil {
+			return mentions, dbConn.InternalError(err)
+		}
+		mentions = append(mentions, mention)
+	}
+
+	if err = rows.Err(); err != nil {
+		return mentions, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// DeleteUser drops the mentions of the user.
+func (s *storage) DeleteUser(ctx context.Context, nickname string) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM mentions WHERE user_nick = $1", nickname)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
+
+// DeleteAuthor drops the mentions made by the user.
+func (s *storage) DeleteAuthor(ctx context.Context, nickname string) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM mentions WHERE author = $1", nickname)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
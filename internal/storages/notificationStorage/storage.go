@@ -0,0 +1,268 @@
+package notificationStorage
+
+import (
+	"context"
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/EgorAist/TP_DB_project/internal/storages/dbConn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx"
+)
+
+type Storage interface {
+	SubscribeThread(ctx context.Context, input models.Subscription) (err error)
+	UnsubscribeThread(ctx context.Context, input models.Subscription) (err error)
+	SubscribeForum(ctx context.Context, input models.Subscription) (err error)
+	UnsubscribeForum(ctx context.Context, input models.Subscription) (err error)
+
+	NotifyPosts(ctx context.Context, posts []int) (err error)
+	NotifyThread(ctx context.Context, thread models.Thread) (err error)
+	NotifyVote(ctx context.Context, thread int, voter string) (err error)
+
+	GetNotifications(ctx context.Context, input models.UserGetNotifications) (notifications []models.Notification, err error)
+	CountUnread(ctx context.Context, nickname string) (unread int, err error)
+	MarkRead(ctx context.Context, input models.NotificationsRead) (err error)
+	GetUserSubscriptions(ctx context.Context, nickname string) (subscriptions []models.Subscription, err error)
+	GetUserNotifications(ctx context.Context, nickname string) (notifications []models.Notification, err error)
+	DeleteUser(ctx context.Context, nickname string) (err error)
+}
+
+type storage struct {
+	db *dbConn.DB
+}
+
+func NewStorage(db *dbConn.DB) Storage {
+	return &storage{
+		db: db,
+	}
+}
+
+func init() {
+	dbConn.RegisterQueries(map[string]string{
+		"notifyPosts":              notifyPosts,
+		"selectNotifications":      selectNotifications,
+		"selectNotificationsSince": selectNotificationsSince,
+	})
+}
+
+// subscriptionError turns a foreign key violation into the missing entity.
+func subscriptionError(err error) error {
+	if pqErr, ok := err.(pgx.PgError); ok && pqErr.Code == pgerrcode.ForeignKeyViolation {
+		return models.NewNotFound(dbConn.ReferencedEntity(pqErr))
+	}
+	return dbConn.InternalError(err)
+}
+
+// SubscribeThread subscribes the user to the thread; subscribing again changes
+// nothing.
+func (s *storage) SubscribeThread(ctx context.Context, input models.Subscription) (err error) {
+	_, err = s.db.Exec(ctx, "INSERT INTO thread_subscriptions (user_nick, thread) VALUES ($1, $2) ON CONFLICT DO NOTHING", input.User, input.Thread)
+	if err != nil {
+		return subscriptionError(err)
+	}
+	return
+}
+
+func (s *storage) UnsubscribeThread(ctx context.Context, input models.Subscription) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM thread_subscriptions WHERE user_nick = $1 AND thread = $2", input.User, input.Thread)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
+
+func (s *storage) SubscribeForum(ctx context.Context, input models.Subscription) (err error) {
+	_, err = s.db.Exec(ctx, "INSERT INTO forum_subscriptions (user_nick, forum) VALUES ($1, $2) ON CONFLICT DO NOTHING", input.User, input.Forum)
+	if err != nil {
+		return subscriptionError(err)
+	}
+	return
+}
+
+func (s *storage) UnsubscribeForum(ctx context.Context, input models.Subscription) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM forum_subscriptions WHERE user_nick = $1 AND forum = $2", input.User, input.Forum)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
+
+// notifyPosts tells the authors of the parents about the replies and the
+// subscribers of the threads about the new posts. A subscriber who also gets
+// the reply is not told twice, and nobody is told about their own posts.
+const notifyPosts = `
+	WITH new AS (
+		SELECT p.ID, p.thread, p.author, parent.author AS parent_author
+		FROM posts p LEFT JOIN posts parent ON p.parent <> 0 AND parent.ID = p.parent
+		WHERE p.ID = ANY($1::INTEGER[])
+	), replies AS (
+		INSERT INTO notifications (user_nick, kind, thread, post, actor)
+		SELECT parent_author, 'reply', thread, ID, author FROM new
+		WHERE parent_author IS NOT NULL AND parent_author <> author
+	)
+	INSERT INTO notifications (user_nick, kind, thread, post, actor)
+	SELECT s.user_nick, 'post', n.thread, n.ID, n.author
+	FROM new n JOIN thread_subscriptions s ON s.thread = n.thread
+	WHERE s.user_nick <> n.author AND s.user_nick IS DISTINCT FROM n.parent_author
+`
+
+// NotifyPosts must run in the unit of work that creates the posts.
+func (s *storage) NotifyPosts(ctx context.Context, posts []int) (err error) {
+	_, err = s.db.Exec(ctx, notifyPosts, posts)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
+
+// NotifyThread tells the subscribers of the forum about the new thread.
+func (s *storage) NotifyThread(ctx context.Context, thread models.Thread) (err error) {
+	_, err = s.db.Exec(ctx, "INSERT INTO notifications (user_nick, kind, thread, actor) "+
+		"SELECT user_nick, 'thread', $1, $2 FROM forum_subscriptions WHERE forum = $3 AND user_nick <> $2",
+		thread.ID, thread.Author, thread.Forum)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
+
+// NotifyVote tells the author of the thread about the vote, unless they cast
+// it themselves.
+func (s *storage) NotifyVote(ctx context.Context, thread int, voter string) (err error) {
+	_, err = s.db.Exec(ctx, "INSERT INTO notifications (user_nick, kind, thread, actor) "+
+		"SELECT author, 'vote', ID, $2 FROM threads WHERE ID = $1 AND author <> $2",
+		thread, voter)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
+
+// Notifications come newest first; since is the ID the page starts after.
+const (
+	selectNotifications = `
+	SELECT ID, kind, thread, coalesce(post, 0), actor, created, read FROM notifications
+	WHERE user_nick = $1 AND (NOT $2::BOOLEAN OR NOT read)
+	ORDER BY ID DESC LIMIT $3`
+	selectNotificationsSince = `
+	SELECT ID, kind, thread, coalesce(post, 0), actor, created, read FROM notifications
+	WHERE user_nick = $1 AND (NOT $2::BOOLEAN OR NOT read) AND ID < $3
+	ORDER BY ID DESC LIMIT $4`
+)
+
+func (s *storage) GetNotifications(ctx context.Context, input models.UserGetNotifications) (notifications []models.Notification, err error) {
+	var rows *dbConn.Rows
+	if input.Since == 0 {
+		rows, err = s.db.Query(ctx, selectNotifications, input.Nickname, input.Unread, input.Limit)
+	} else {
+		rows, err = s.db.Query(ctx, selectNotificationsSince, input.Nickname, input.Unread, input.Since, input.Limit)
+	}
+	if err != nil {
+		return notifications, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	notifications = make([]models.Notification, 0)
+	for rows.Next() {
+		notification := models.Notification{}
+		err = rows.Scan(&notification.ID, &notification.Kind, &notification.Thread, &notification.Post,
+			&notification.Actor, &notification.Created, &notification.Read)
+		if err != nil {
+			return notifications, dbConn.InternalError(err)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err = rows.Err(); err != nil {
+		return notifications, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) CountUnread(ctx context.Context, nickname string) (unread int, err error) {
+	err = s.db.QueryRow(ctx, "SELECT count(*) FROM notifications WHERE user_nick = $1 AND NOT read", nickname).Scan(&unread)
+	if err != nil {
+		return 0, dbConn.InternalError(err)
+	}
+	return
+}
+
+// MarkRead marks the notifications of the user with the given IDs, or all of
+// them, as read. IDs of other users' notifications are ignored.
+func (s *storage) MarkRead(ctx context.Context, input models.NotificationsRead) (err error) {
+	if input.All {
+		_, err = s.db.Exec(ctx, "UPDATE notifications SET read = true WHERE user_nick = $1 AND NOT read", input.Nickname)
+	} else {
+		_, err = s.db.Exec(ctx, "UPDATE notifications SET read = true WHERE user_nick = $1 AND ID = ANY($2::INTEGER[]) AND NOT read", input.Nickname, input.IDs)
+	}
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
+
+// GetUserSubscriptions lists the threads the user is subscribed to, then the
+// forums.
+func (s *storage) GetUserSubscriptions(ctx context.Context, nickname string) (subscriptions []models.Subscription, err error) {
+	rows, err := s.db.Query(ctx, "SELECT user_nick, thread, '' FROM thread_subscriptions WHERE user_nick = $1 "+
+		"UNION ALL SELECT user_nick, 0, forum::TEXT FROM forum_subscriptions WHERE user_nick = $1", nickname)
+	if err != nil {
+		return subscriptions, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	subscriptions = make([]models.Subscription, 0)
+	for rows.Next() {
+		subscription := models.Subscription{}
+		if err = rows.Scan(&subscription.User, &subscription.Thread, &subscription.Forum); err != nil {
+			return subscriptions, dbConn.InternalError(err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		return subscriptions, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// GetUserNotifications lists every notification of the user, oldest first.
+func (s *storage) GetUserNotifications(ctx context.Context, nickname string) (notifications []models.Notification, err error) {
+	rows, err := s.db.Query(ctx, "SELECT ID, kind, thread, coalesce(post, 0), actor, created, read FROM notifications WHERE user_nick = $1 ORDER BY ID", nickname)
+	if err != nil {
+		return notifications, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	notifications = make([]models.Notification, 0)
+	for rows.Next() {
+		notification := models.Notification{}
+		err = rows.Scan(&notification.ID, &notification.Kind, &notification.Thread, &notification.Post,
+			&notification.Actor, &notification.Created, &notification.Read)
+		if err != nil {
+			return notifications, dbConn.InternalError(err)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err = rows.Err(); err != nil {
+		return notifications, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// DeleteUser drops the subscriptions and the notifications of the user.
+func (s *storage) DeleteUser(ctx context.Context, nickname string) (err error) {
+	for _, query := range []string{
+		"DELETE FROM thread_subscriptions WHERE user_nick = $1",
+		"DELETE FROM forum_subscriptions WHERE user_nick = $1",
+		"DELETE FROM notifications WHERE user_nick = $1 OR actor = $1",
+	} {
+		if _, err = s.db.Exec(ctx, query, nickname); err != nil {
+			return dbConn.InternalError(err)
+		}
+	}
+	return
+}
@@ -1,33 +1,77 @@
 package postStorage
 
 import (
+	"context"
 	"fmt"
 	"github.com/EgorAist/TP_DB_project/internal/models"
 	"github.com/jackc/pgerrcode"
+	"github.com/EgorAist/TP_DB_project/internal/storages/dbConn"
 	"github.com/jackc/pgx"
 	"strings"
 )
 
 type Storage interface {
-	CreatePosts(thread models.ThreadInput, forum string, created string, posts []models.PostCreate) (post []models.Post, err error)
-	CreatePost(input models.Post) (post models.Post, err error)
-	GetPostDetails(input models.PostInput, post *models.Post) (err error)
-	UpdatePost(input models.PostUpdate) (post models.Post, err error)
-	GetPostsByThread(input models.ThreadGetPosts) (posts []models.Post, err error)
-	CheckParentPostThread(post int) (thread int, err error)
+	CreatePosts(ctx context.Context, thread models.ThreadInput, forum string, created string, posts []models.PostCreate) (post []models.Post, err error)
+	CreatePost(ctx context.Context, input models.Post) (post models.Post, err error)
+	GetPostDetails(ctx context.Context, input models.PostInput, post *models.Post) (err error)
+	LockPost(ctx context.Context, input models.PostInput) (err error)
+	UpdatePost(ctx context.Context, input models.PostUpdate) (post models.Post, err error)
+	GetRevisions(ctx context.Context, input models.PostGetHistory) (revisions []models.PostRevision, err error)
+	DeletePost(ctx context.Context, input models.PostInput) (post models.Post, deleted bool, err error)
+	PurgePostSubtree(ctx context.Context, input models.PostInput) (forum string, posts int, live int, err error)
+	GetPostsByAuthor(ctx context.Context, nickname string) (posts []models.Post, err error)
+	GetPostsByUser(ctx context.Context, input models.UserGetPosts) (posts []models.Post, err error)
+	TombstoneAuthorPosts(ctx context.Context, nickname string) (counters []models.ForumCounters, posts int, err error)
+	GetEditorRevisions(ctx context.Context, nickname string) (revisions []models.UserPostRevision, err error)
+	DeleteEditorRevisions(ctx context.Context, nickname string) (err error)
+	GetPostsByThread(ctx context.Context, input models.ThreadGetPosts) (posts []models.Post, err error)
+	CheckParentPostThread(ctx context.Context, post int) (thread int, err error)
 }
 
+// parentConflict is the SQLSTATE raised by the update_path trigger.
+const parentConflict = "00409"
+
 type storage struct {
-	db *pgx.ConnPool
+	db *dbConn.DB
 }
 
-func NewStorage(db *pgx.ConnPool) Storage {
+func NewStorage(db *dbConn.DB) Storage {
 	return &storage{
 		db: db,
 	}
 }
 
-func (s storage) CreatePosts(thread models.ThreadInput, forum string, created string, posts []models.PostCreate) (post []models.Post, err error) {
+func init() {
+	dbConn.RegisterQueries(map[string]string{
+		"selectPostsFlatLimitByID":                selectPostsFlatLimitByID,
+		"selectPostsFlatLimitDescByID":            selectPostsFlatLimitDescByID,
+		"selectPostsFlatLimitSinceByID":           selectPostsFlatLimitSinceByID,
+		"selectPostsFlatLimitSinceDescByID":       selectPostsFlatLimitSinceDescByID,
+		"selectPostsTreeLimitByID":                selectPostsTreeLimitByID,
+		"selectPostsTreeLimitDescByID":            selectPostsTreeLimitDescByID,
+		"selectPostsTreeLimitSinceByID":           selectPostsTreeLimitSinceByID,
+		"selectPostsTreeLimitSinceDescByID":       selectPostsTreeLimitSinceDescByID,
+		"selectPostsParentTreeLimitByID":          selectPostsParentTreeLimitByID,
+		"selectPostsParentTreeLimitDescByID":      selectPostsParentTreeLimitDescByID,
+		"selectPostsParentTreeLimitSinceByID":     selectPostsParentTreeLimitSinceByID,
+		"selectPostsParentTreeLimitSinceDescByID": selectPostsParentTreeLimitSinceDescByID,
+		"selectPostsTopByID":                      selectPostsTopByID,
+		"selectPostsTopDescByID":                  selectPostsTopDescByID,
+		"selectPostsTopSinceByID":                 selectPostsTopSinceByID,
+		"selectPostsTopSinceDescByID":             selectPostsTopSinceDescByID,
+		"purgePostSubtree":                        purgePostSubtree,
+		"tombstoneAuthorPosts":                    tombstoneAuthorPosts,
+		"selectUserPosts":                         selectUserPosts,
+		"selectUserPostsSince":                    selectUserPostsSince,
+		"selectUserPostsDesc":                     selectUserPostsDesc,
+		"selectUserPostsSinceDesc":                selectUserPostsSinceDesc,
+		"updatePostWithRevision":                  updatePostWithRevision,
+		"selectRevisions":                         selectRevisions,
+		"selectRevisionsDesc":                     selectRevisionsDesc,
+	})
+}
+
+func (s storage) CreatePosts(ctx context.Context, thread models.ThreadInput, forum string, created string, posts []models.PostCreate) (post []models.Post, err error) {
 	query := `INSERT INTO posts(
                  author,
                  created,
@@ -54,10 +98,9 @@ func (s storage) CreatePosts(thread models.ThreadInput, forum string, created st
 
 	query += strings.Join(valNam[:], ",")
 	query += " RETURNING  id, parent, thread, forum, author, created, message, edited"
-	row, err := s.db.Query(query, values...)
+	row, err := s.db.Query(ctx, query, values...)
 	if err != nil {
-		fmt.Println(err)
-		return data, err
+		return data, dbConn.InternalError(err)
 	}
 
 	defer func() {
@@ -72,38 +115,52 @@ func (s storage) CreatePosts(thread models.ThreadInput, forum string, created st
 		err = row.Scan(&scanPost.ID, &scanPost.Parent, &scanPost.ThreadID, &scanPost.Forum,  &scanPost.Author, &scanPost.Created,&scanPost.Message, &scanPost.IsEdited)
 
 		if err != nil {
-			fmt.Println(err)
-			return data, err
+			return data, dbConn.InternalError(err)
 		}
 		data = append(data, scanPost)
 	}
 
-	if len(data) == 0 {
-		return data, models.Error{Code: "409"}
+	// Constraint and trigger failures of the batch surface here: update_path
+	// raises 00409 when a parent is missing or belongs to another thread.
+	if err = row.Err(); err != nil {
+		if pqErr, ok := err.(pgx.PgError); ok {
+			switch pqErr.Code {
+			case parentConflict:
+				return data, models.NewConflict(models.EntityPost, models.ReasonParentConflict, "parent post is not in this thread")
+			case pgerrcode.NotNullViolation, pgerrcode.ForeignKeyViolation:
+				return data, models.NewNotFound(dbConn.ReferencedEntity(pqErr))
+			}
+		}
+		return data, dbConn.InternalError(err)
 	}
-	return data, err
+
+	return data, nil
 }
 
-func (s *storage) CreatePost(input models.Post) (post models.Post, err error) {
+func (s *storage) CreatePost(ctx context.Context, input models.Post) (post models.Post, err error) {
 	if input.Parent == 0 {
-		err = s.db.QueryRow("INSERT INTO posts (author, created, forum, message, parent, thread, path) VALUES ($1,$2,$3,$4,$5,$6, array[(select currval('post_id_seq')::integer)]) RETURNING ID",
+		err = s.db.QueryRow(ctx, "INSERT INTO posts (author, created, forum, message, parent, thread, path) VALUES ($1,$2,$3,$4,$5,$6, array[(select currval('post_id_seq')::integer)]) RETURNING ID",
 			input.Author, input.Created, input.Forum, input.Message, input.Parent, input.ThreadInput.ThreadID).Scan(&post.ID)
 	} else {
-		err = s.db.QueryRow("INSERT INTO posts (author, created, forum, message, parent, thread, path) VALUES ($1,$2,$3,$4,$5,$6, (SELECT path FROM posts WHERE id = $5) || (select currval('post_id_seq')::integer)) RETURNING ID",
+		err = s.db.QueryRow(ctx, "INSERT INTO posts (author, created, forum, message, parent, thread, path) VALUES ($1,$2,$3,$4,$5,$6, (SELECT path FROM posts WHERE id = $5) || (select currval('post_id_seq')::integer)) RETURNING ID",
 			input.Author, input.Created, input.Forum, input.Message, input.Parent, input.ThreadInput.ThreadID).Scan(&post.ID)
 	}
 
 	if pqErr, ok := err.(pgx.PgError); ok {
 		switch pqErr.Code {
 		case pgerrcode.UniqueViolation:
-			return post, models.Error{Code: "409", Message: "conflict post"}
+			return post, models.NewConflict(models.EntityPost, models.ReasonAlreadyExists, "post already exists")
 		case pgerrcode.NotNullViolation, pgerrcode.ForeignKeyViolation:
-			return post, models.Error{Code: "404", Message: "conflict post"}
+			return post, models.NewNotFound(dbConn.ReferencedEntity(pqErr))
 		default:
-			return post, models.Error{Code: "500", Message: "conflict post"}
+			return post, dbConn.InternalError(err)
 		}
 	}
 
+	if err != nil {
+		return post, dbConn.InternalError(err)
+	}
+
 	post.Author = input.Author
 	post.Created = input.Created
 	post.Forum = input.Forum
@@ -115,46 +172,185 @@ func (s *storage) CreatePost(input models.Post) (post models.Post, err error) {
 	return
 }
 
-func (s *storage) GetPostDetails(input models.PostInput, post *models.Post) (err error) {
-	err = s.db.QueryRow("SELECT author, created, forum, message, ID , edited, parent, thread FROM posts WHERE ID = $1", input.ID).
-				Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID)
+func (s *storage) GetPostDetails(ctx context.Context, input models.PostInput, post *models.Post) (err error) {
+	err = s.db.QueryRow(ctx, "SELECT author, created, forum, message, ID , edited, parent, thread, deleted, score FROM posts WHERE ID = $1 AND NOT EXISTS (SELECT 1 FROM threads t WHERE t.ID = posts.thread AND t.state = 'deleted')", input.ID).
+				Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID, &post.IsDeleted, &post.Score)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return models.Error{Code: "404"}
+			return models.NewNotFound(models.EntityPost)
 
 		}
-		return models.Error{Code: "500"}
+		return dbConn.InternalError(err)
 	}
 	return
 }
 
-func (s *storage) UpdatePost(input models.PostUpdate) (post models.Post, err error) {
+// LockPost locks the post row until the unit of work ends, so votes on one
+// post take their turns.
+func (s *storage) LockPost(ctx context.Context, input models.PostInput) (err error) {
+	var id int
+	err = s.db.QueryRow(ctx, "SELECT ID FROM posts WHERE ID = $1 FOR UPDATE", input.ID).Scan(&id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.NewNotFound(models.EntityPost)
+		}
+		return dbConn.InternalError(err)
+	}
+	return
+}
+
+// updatePostWithRevision keeps the replaced message as the next revision of the
+// post. The editor defaults to the author.
+const updatePostWithRevision = `
+	WITH revision AS (
+		INSERT INTO post_revisions (post, revision, message, editor)
+		SELECT p.ID, coalesce((SELECT max(r.revision) FROM post_revisions r WHERE r.post = p.ID), 0) + 1,
+			p.message, coalesce(NULLIF($3, '')::CITEXT, p.author)
+		FROM posts p WHERE p.ID = $2
+	)
+	UPDATE posts SET message = $1, edited = true WHERE ID = $2
+	RETURNING author, created, forum, message, ID, edited, parent, thread, score
+`
+
+// UpdatePost must run inside a unit of work: the post is locked until the
+// revision with its old message is stored.
+func (s *storage) UpdatePost(ctx context.Context, input models.PostUpdate) (post models.Post, err error) {
 	var oldMessage string
-	err = s.db.QueryRow("SELECT message FROM posts WHERE ID = $1", input.ID).
-		Scan(&oldMessage)
+	var deleted bool
+	err = s.db.QueryRow(ctx, "SELECT message, deleted FROM posts WHERE ID = $1 FOR UPDATE", input.ID).
+		Scan(&oldMessage, &deleted)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return post, models.Error{Code: "404"}
+			return post, models.NewNotFound(models.EntityPost)
 		}
-		return post, models.Error{Code: "500"}
+		return post, dbConn.InternalError(err)
+	}
+	if deleted {
+		return post, models.NewConflict(models.EntityPost, models.ReasonDeleted, "post is deleted")
 	}
 
 	if input.Message != "" && input.Message != oldMessage {
-		err = s.db.QueryRow("UPDATE posts SET message = $1, edited = $2 WHERE ID = $3 RETURNING author, created, forum, message, ID , edited, parent, thread", input.Message, true, input.ID).
-			Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID)
+		err = s.db.QueryRow(ctx, updatePostWithRevision, input.Message, input.ID, input.Editor).
+			Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID, &post.Score)
+		if pqErr, ok := err.(pgx.PgError); ok && pqErr.Code == pgerrcode.ForeignKeyViolation {
+			return post, models.NewNotFound(models.EntityUser)
+		}
 	} else {
-		err = s.db.QueryRow("SELECT author, created, forum, message, ID , edited, parent, thread FROM posts WHERE ID = $1", input.ID).
-			Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID)
+		err = s.db.QueryRow(ctx, "SELECT author, created, forum, message, ID , edited, parent, thread, score FROM posts WHERE ID = $1", input.ID).
+			Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID, &post.Score)
 		}
 
 	if err != nil {
-		return post, models.Error{Code: "500"}
+		return post, dbConn.InternalError(err)
 	}
 	return
 }
 
+// DeletePost turns the post into a tombstone: the message is replaced and the
+// row stays, keeping its path for the replies. deleted is false when the post
+// was already a tombstone.
+func (s *storage) DeletePost(ctx context.Context, input models.PostInput) (post models.Post, deleted bool, err error) {
+	err = s.db.QueryRow(ctx, "UPDATE posts SET message = $2, deleted = true, deleted_at = now() WHERE ID = $1 AND NOT deleted RETURNING author, created, forum, message, ID , edited, parent, thread, deleted, score", input.ID, models.DeletedPostMessage).
+		Scan(&post.Author, &post.Created, &post.Forum, &post.Message, &post.ID, &post.IsEdited, &post.Parent, &post.ThreadInput.ThreadID, &post.IsDeleted, &post.Score)
+	if err == pgx.ErrNoRows {
+		return post, false, s.GetPostDetails(ctx, input, &post)
+	}
+	if err != nil {
+		return post, false, dbConn.InternalError(err)
+	}
+
+	// The old messages go with the current one.
+	_, err = s.db.Exec(ctx, "DELETE FROM post_revisions WHERE post = $1", input.ID)
+	if err != nil {
+		return post, false, dbConn.InternalError(err)
+	}
+
+	return post, true, nil
+}
+
+const (
+	selectRevisions = `
+	SELECT revision, message, editor, edited, next FROM (
+		SELECT r.revision, r.message, r.editor, r.edited,
+			coalesce(lead(r.message) OVER (ORDER BY r.revision), p.message) AS next
+		FROM post_revisions r JOIN posts p ON p.ID = r.post
+		WHERE r.post = $1
+	) h ORDER BY revision LIMIT $2`
+	selectRevisionsDesc = `
+	SELECT revision, message, editor, edited, next FROM (
+		SELECT r.revision, r.message, r.editor, r.edited,
+			coalesce(lead(r.message) OVER (ORDER BY r.revision), p.message) AS next
+		FROM post_revisions r JOIN posts p ON p.ID = r.post
+		WHERE r.post = $1
+	) h ORDER BY revision DESC LIMIT $2`
+)
+
+// GetRevisions lists the revisions of the post, each with the message that
+// replaced it in Next.
+func (s *storage) GetRevisions(ctx context.Context, input models.PostGetHistory) (revisions []models.PostRevision, err error) {
+	query := selectRevisions
+	if input.Desc {
+		query = selectRevisionsDesc
+	}
+
+	rows, err := s.db.Query(ctx, query, input.ID, input.Limit)
+	if err != nil {
+		return revisions, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	revisions = make([]models.PostRevision, 0)
+	for rows.Next() {
+		revision := models.PostRevision{}
+		if err = rows.Scan(&revision.Revision, &revision.Message, &revision.Editor, &revision.Edited, &revision.Next); err != nil {
+			return revisions, dbConn.InternalError(err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return revisions, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// purgePostSubtree leaves posts of deleted threads alone: DeleteThread has
+// already taken their live posts off forums.posts. The thread row is shared
+// locked so the thread cannot be deleted while the subtree is counted.
+const purgePostSubtree = `
+	WITH root AS (
+		SELECT p.thread, p.forum FROM posts p
+		JOIN threads t ON t.ID = p.thread
+		WHERE p.id = $1 AND t.state <> 'deleted'
+		FOR SHARE OF t
+	), purged AS (
+		DELETE FROM posts p
+		USING root
+		WHERE p.thread = root.thread AND p.path @> ARRAY[$1::INTEGER]
+		RETURNING p.deleted
+	)
+	SELECT coalesce((SELECT forum FROM root), ''), count(*), count(*) FILTER (WHERE NOT deleted)
+	FROM purged
+`
+
+// PurgePostSubtree removes the post and all of its replies. live counts the
+// removed posts that were not tombstones yet. A post of a deleted thread is
+// not found.
+func (s *storage) PurgePostSubtree(ctx context.Context, input models.PostInput) (forum string, posts int, live int, err error) {
+	err = s.db.QueryRow(ctx, purgePostSubtree, input.ID).Scan(&forum, &posts, &live)
+	if err != nil {
+		return "", 0, 0, dbConn.InternalError(err)
+	}
+	if posts == 0 {
+		return "", 0, 0, models.NewNotFound(models.EntityPost)
+	}
+
+	return forum, posts, live, nil
+}
+
 const selectPostsFlatLimitByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
 	FROM posts p
 	WHERE p.thread = $1
 	ORDER BY p.created, p.id
@@ -162,56 +358,56 @@ const selectPostsFlatLimitByID = `
 `
 
 const selectPostsFlatLimitDescByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
 	FROM posts p
 	WHERE p.thread = $1
 	ORDER BY p.created DESC, p.id DESC
 	LIMIT $2
 `
 const selectPostsFlatLimitSinceByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
 	FROM posts p
 	WHERE p.thread = $1 and p.id > $2
 	ORDER BY p.created, p.id
 	LIMIT $3
 `
 const selectPostsFlatLimitSinceDescByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
 	FROM posts p
 	WHERE p.thread = $1 and p.id < $2
 	ORDER BY p.created DESC, p.id DESC
 	LIMIT $3
 `
 const selectPostsTreeLimitByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
 	FROM posts p
 	WHERE p.thread = $1
 	ORDER BY p.path
 	LIMIT $2
 `
 const selectPostsTreeLimitDescByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
 	FROM posts p
 	WHERE p.thread = $1
 	ORDER BY path DESC
 	LIMIT $2
 `
 const selectPostsTreeLimitSinceByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
 	FROM posts p
 	WHERE p.thread = $1 and (p.path > (SELECT p2.path from posts p2 where p2.id = $2))
 	ORDER BY p.path
 	LIMIT $3
 `
 const selectPostsTreeLimitSinceDescByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
 	FROM posts p
 	WHERE p.thread = $1 and (p.path < (SELECT p2.path from posts p2 where p2.id = $2))
 	ORDER BY p.path DESC
 	LIMIT $3
 `
 const selectPostsParentTreeLimitByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
 	FROM posts p
 	WHERE p.thread = $1 and p.path[1] IN (
 		SELECT p2.path[1]
@@ -223,7 +419,7 @@ const selectPostsParentTreeLimitByID = `
 	ORDER BY path
 `
 const selectPostsParentTreeLimitDescByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
 	FROM posts p
 	WHERE p.thread = $1 and p.path[1] IN (
 		SELECT p2.path[1]
@@ -236,7 +432,7 @@ const selectPostsParentTreeLimitDescByID = `
 `
 
 const selectPostsParentTreeLimitSinceByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
 	FROM posts p
 	WHERE p.thread = $1 and p.path[1] IN (
 		SELECT p2.path[1]
@@ -248,7 +444,7 @@ const selectPostsParentTreeLimitSinceByID = `
 	ORDER BY p.path
 `
 const selectPostsParentTreeLimitSinceDescByID = `
-	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
 	FROM posts p
 	WHERE p.thread = $1 and p.path[1] IN (
 		SELECT p2.path[1]
@@ -260,109 +456,306 @@ const selectPostsParentTreeLimitSinceDescByID = `
 	ORDER BY p.path[1] DESC, p.path[2:]
 `
 
-func (s *storage) GetPostsByThread(input models.ThreadGetPosts) (posts []models.Post, err error){
-	var rows *pgx.Rows
+// The top sort orders posts by score, best first, then by ID. since is the ID
+// of the post the page starts after.
+const (
+	selectPostsTopByID = `
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
+	FROM posts p
+	WHERE p.thread = $1
+	ORDER BY p.score DESC, p.id
+	LIMIT $2`
+	selectPostsTopDescByID = `
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
+	FROM posts p
+	WHERE p.thread = $1
+	ORDER BY p.score, p.id DESC
+	LIMIT $2`
+	selectPostsTopSinceByID = `
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
+	FROM posts p, (SELECT score, id FROM posts WHERE id = $2) since
+	WHERE p.thread = $1 AND (p.score < since.score OR (p.score = since.score AND p.id > since.id))
+	ORDER BY p.score DESC, p.id
+	LIMIT $3`
+	selectPostsTopSinceDescByID = `
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
+	FROM posts p, (SELECT score, id FROM posts WHERE id = $2) since
+	WHERE p.thread = $1 AND (p.score > since.score OR (p.score = since.score AND p.id < since.id))
+	ORDER BY p.score, p.id DESC
+	LIMIT $3`
+)
+
+func (s *storage) GetPostsByThread(ctx context.Context, input models.ThreadGetPosts) (posts []models.Post, err error){
+	var rows *dbConn.Rows
 	posts  = make([]models.Post, 0)
 	switch input.Sort {
 	case "flat":
 		if input.Since > 0 {
 			if input.Desc {
-				rows, err = s.db.Query(selectPostsFlatLimitSinceDescByID, input.ThreadInput.ThreadID,
+				rows, err = s.db.Query(ctx, selectPostsFlatLimitSinceDescByID, input.ThreadInput.ThreadID,
 					input.Since, input.Limit)
 			} else {
-				rows, err = s.db.Query(selectPostsFlatLimitSinceByID, input.ThreadInput.ThreadID,
+				rows, err = s.db.Query(ctx, selectPostsFlatLimitSinceByID, input.ThreadInput.ThreadID,
 					input.Since, input.Limit)
 			}
 		} else {
 			if input.Desc == true {
-				rows, err = s.db.Query(selectPostsFlatLimitDescByID, input.ThreadInput.ThreadID, input.Limit)
+				rows, err = s.db.Query(ctx, selectPostsFlatLimitDescByID, input.ThreadInput.ThreadID, input.Limit)
 			} else {
-				rows, err = s.db.Query(selectPostsFlatLimitByID, input.ThreadInput.ThreadID, input.Limit)
+				rows, err = s.db.Query(ctx, selectPostsFlatLimitByID, input.ThreadInput.ThreadID, input.Limit)
 			}
 		}
 	case "tree":
 		if input.Since > 0 {
 			if input.Desc {
-				rows, err = s.db.Query(selectPostsTreeLimitSinceDescByID, input.ThreadInput.ThreadID,
+				rows, err = s.db.Query(ctx, selectPostsTreeLimitSinceDescByID, input.ThreadInput.ThreadID,
 					input.Since, input.Limit)
 			} else {
-				rows, err = s.db.Query(selectPostsTreeLimitSinceByID, input.ThreadInput.ThreadID,
+				rows, err = s.db.Query(ctx, selectPostsTreeLimitSinceByID, input.ThreadInput.ThreadID,
 					input.Since, input.Limit)
 			}
 		} else {
 			if input.Desc {
-				rows, err = s.db.Query(selectPostsTreeLimitDescByID, input.ThreadInput.ThreadID, input.Limit)
+				rows, err = s.db.Query(ctx, selectPostsTreeLimitDescByID, input.ThreadInput.ThreadID, input.Limit)
 			} else {
-				rows, err = s.db.Query(selectPostsTreeLimitByID, input.ThreadInput.ThreadID, input.Limit)
+				rows, err = s.db.Query(ctx, selectPostsTreeLimitByID, input.ThreadInput.ThreadID, input.Limit)
 			}
 		}
 	case "parent_tree":
 		if input.Since > 0 {
 			if input.Desc {
-				rows, err = s.db.Query(selectPostsParentTreeLimitSinceDescByID, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
+				rows, err = s.db.Query(ctx, selectPostsParentTreeLimitSinceDescByID, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
 					input.Since, input.Limit)
 			} else {
-				rows, err = s.db.Query(selectPostsParentTreeLimitSinceByID, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
+				rows, err = s.db.Query(ctx, selectPostsParentTreeLimitSinceByID, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
 					input.Since, input.Limit)
 			}
 		} else {
 			if input.Desc {
-				rows, err = s.db.Query(selectPostsParentTreeLimitDescByID, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
+				rows, err = s.db.Query(ctx, selectPostsParentTreeLimitDescByID, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
 					input.Limit)
 			} else {
-				rows, err = s.db.Query(selectPostsParentTreeLimitByID, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
+				rows, err = s.db.Query(ctx, selectPostsParentTreeLimitByID, input.ThreadInput.ThreadID, input.ThreadInput.ThreadID,
 					input.Limit)
 			}
 		}
+	case "top":
+		if input.Since > 0 {
+			if input.Desc {
+				rows, err = s.db.Query(ctx, selectPostsTopSinceDescByID, input.ThreadInput.ThreadID,
+					input.Since, input.Limit)
+			} else {
+				rows, err = s.db.Query(ctx, selectPostsTopSinceByID, input.ThreadInput.ThreadID,
+					input.Since, input.Limit)
+			}
+		} else {
+			if input.Desc {
+				rows, err = s.db.Query(ctx, selectPostsTopDescByID, input.ThreadInput.ThreadID, input.Limit)
+			} else {
+				rows, err = s.db.Query(ctx, selectPostsTopByID, input.ThreadInput.ThreadID, input.Limit)
+			}
+		}
 	default:
 		if input.Since > 0 {
 			if input.Desc {
-				rows, err = s.db.Query(selectPostsFlatLimitSinceDescByID, input.ThreadInput.ThreadID,
+				rows, err = s.db.Query(ctx, selectPostsFlatLimitSinceDescByID, input.ThreadInput.ThreadID,
 					input.Since, input.Limit)
 			} else {
-				rows, err = s.db.Query(selectPostsFlatLimitSinceByID, input.ThreadInput.ThreadID,
+				rows, err = s.db.Query(ctx, selectPostsFlatLimitSinceByID, input.ThreadInput.ThreadID,
 					input.Since, input.Limit)
 			}
 		} else {
 			if input.Desc == true {
-				rows, err = s.db.Query(selectPostsFlatLimitDescByID, input.ThreadInput.ThreadID, input.Limit)
+				rows, err = s.db.Query(ctx, selectPostsFlatLimitDescByID, input.ThreadInput.ThreadID, input.Limit)
 			} else {
-				rows, err = s.db.Query(selectPostsFlatLimitByID, input.ThreadInput.ThreadID, input.Limit)
+				rows, err = s.db.Query(ctx, selectPostsFlatLimitByID, input.ThreadInput.ThreadID, input.Limit)
 			}
 		}
 	}
 
 	if err != nil {
-		return posts, models.Error{Code: "500"}
+		return posts, dbConn.InternalError(err)
 	}
 	defer rows.Close()
 
 	if rows == nil {
-		return posts, models.Error{Code: "500"}
+		return posts, dbConn.InternalError(err)
 	}
 
 	for rows.Next() {
 		post := models.Post{}
 
-		err = rows.Scan(&post.ID, &post.Author, &post.Created, &post.IsEdited, &post.Message, &post.Parent, &post.ThreadInput.ThreadID, &post.Forum)
+		err = rows.Scan(&post.ID, &post.Author, &post.Created, &post.IsEdited, &post.Message, &post.Parent, &post.ThreadInput.ThreadID, &post.Forum, &post.IsDeleted, &post.Score)
 		if err != nil {
-			return posts, models.Error{Code: "500"}
+			return posts, dbConn.InternalError(err)
 		}
 
 		posts = append(posts, post)
 	}
 
+	if err = rows.Err(); err != nil {
+		return posts, dbConn.InternalError(err)
+	}
+
 	return 
 }
 
-func (s storage) CheckParentPostThread(post int) (thread int, err error) {
-	err = s.db.QueryRow("SELECT thread FROM posts WHERE ID = $1", post).Scan(&thread)
+func (s storage) CheckParentPostThread(ctx context.Context, post int) (thread int, err error) {
+	err = s.db.QueryRow(ctx, "SELECT thread FROM posts WHERE ID = $1", post).Scan(&thread)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return 0, models.Error{Code: "409"}
+			return 0, models.NewConflict(models.EntityPost, models.ReasonParentConflict, "parent post not found")
+		}
+		return 0, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) GetPostsByAuthor(ctx context.Context, nickname string) (posts []models.Post, err error) {
+	rows, err := s.db.Query(ctx, "SELECT id, author, created, edited, message, parent, thread, forum, deleted, score FROM posts WHERE author = $1 ORDER BY id", nickname)
+	if err != nil {
+		return posts, dbConn.InternalError(err)
+	}
+	return scanPosts(rows)
+}
+
+// The posts of a user leave out tombstones and the posts of deleted threads.
+// An empty forum ($2) lists them in every forum.
+const (
+	selectUserPosts = `
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
+	FROM posts p JOIN threads t ON t.ID = p.thread
+	WHERE p.author = $1 AND NOT p.deleted AND t.state <> 'deleted' AND ($2::CITEXT = '' OR p.forum = $2::CITEXT)
+	ORDER BY p.id LIMIT $3`
+	selectUserPostsSince = `
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
+	FROM posts p JOIN threads t ON t.ID = p.thread
+	WHERE p.author = $1 AND NOT p.deleted AND t.state <> 'deleted' AND ($2::CITEXT = '' OR p.forum = $2::CITEXT) AND p.id > $3
+	ORDER BY p.id LIMIT $4`
+	selectUserPostsDesc = `
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
+	FROM posts p JOIN threads t ON t.ID = p.thread
+	WHERE p.author = $1 AND NOT p.deleted AND t.state <> 'deleted' AND ($2::CITEXT = '' OR p.forum = $2::CITEXT)
+	ORDER BY p.id DESC LIMIT $3`
+	selectUserPostsSinceDesc = `
+	SELECT p.id, p.author, p.created, p.edited, p.message, p.parent, p.thread, p.forum, p.deleted, p.score
+	FROM posts p JOIN threads t ON t.ID = p.thread
+	WHERE p.author = $1 AND NOT p.deleted AND t.state <> 'deleted' AND ($2::CITEXT = '' OR p.forum = $2::CITEXT) AND p.id < $3
+	ORDER BY p.id DESC LIMIT $4`
+)
+
+func (s *storage) GetPostsByUser(ctx context.Context, input models.UserGetPosts) (posts []models.Post, err error) {
+	var rows *dbConn.Rows
+	if input.Since == 0 && !input.Desc {
+		rows, err = s.db.Query(ctx, selectUserPosts, input.Nickname, input.Forum, input.Limit)
+	} else if input.Since == 0 && input.Desc {
+		rows, err = s.db.Query(ctx, selectUserPostsDesc, input.Nickname, input.Forum, input.Limit)
+	} else if input.Since != 0 && !input.Desc {
+		rows, err = s.db.Query(ctx, selectUserPostsSince, input.Nickname, input.Forum, input.Since, input.Limit)
+	} else {
+		rows, err = s.db.Query(ctx, selectUserPostsSinceDesc, input.Nickname, input.Forum, input.Since, input.Limit)
+	}
+	if err != nil {
+		return posts, dbConn.InternalError(err)
+	}
+	return scanPosts(rows)
+}
+
+func scanPosts(rows *dbConn.Rows) (posts []models.Post, err error) {
+	defer rows.Close()
+
+	posts = make([]models.Post, 0)
+	for rows.Next() {
+		post := models.Post{}
+
+		err = rows.Scan(&post.ID, &post.Author, &post.Created, &post.IsEdited, &post.Message, &post.Parent, &post.ThreadInput.ThreadID, &post.Forum, &post.IsDeleted, &post.Score)
+		if err != nil {
+			return posts, dbConn.InternalError(err)
+		}
+
+		posts = append(posts, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return posts, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// tombstoneAuthorPosts turns every post of a user into a tombstone and drops
+// their revisions. Posts in deleted threads are no longer counted in forums
+// and are left out of the counters.
+const tombstoneAuthorPosts = `
+	WITH tombstoned AS (
+		UPDATE posts p SET message = $2, deleted = true, deleted_at = now()
+		WHERE p.author = $1 AND NOT p.deleted
+		RETURNING p.ID, p.forum, p.thread
+	), forgotten AS (
+		DELETE FROM post_revisions r USING tombstoned tb WHERE r.post = tb.ID
+	)
+	SELECT tb.forum, count(*)::INTEGER, (count(*) FILTER (WHERE t.state <> 'deleted'))::INTEGER
+	FROM tombstoned tb
+	JOIN threads t ON t.ID = tb.thread
+	GROUP BY tb.forum
+`
+
+func (s *storage) TombstoneAuthorPosts(ctx context.Context, nickname string) (counters []models.ForumCounters, posts int, err error) {
+	rows, err := s.db.Query(ctx, tombstoneAuthorPosts, nickname, models.DeletedPostMessage)
+	if err != nil {
+		return nil, 0, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var counter models.ForumCounters
+		var forumPosts int
+		if err = rows.Scan(&counter.Forum, &forumPosts, &counter.Posts); err != nil {
+			return nil, 0, dbConn.InternalError(err)
+		}
+		posts += forumPosts
+		counters = append(counters, counter)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// GetEditorRevisions lists the revisions of posts the user made by editing
+// them.
+func (s *storage) GetEditorRevisions(ctx context.Context, nickname string) (revisions []models.UserPostRevision, err error) {
+	rows, err := s.db.Query(ctx, "SELECT post, revision, message, edited FROM post_revisions WHERE editor = $1 ORDER BY post, revision", nickname)
+	if err != nil {
+		return revisions, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	revisions = make([]models.UserPostRevision, 0)
+	for rows.Next() {
+		revision := models.UserPostRevision{}
+		if err = rows.Scan(&revision.Post, &revision.Revision, &revision.Message, &revision.Edited); err != nil {
+			return revisions, dbConn.InternalError(err)
 		}
-		return 0, models.Error{Code: "500"}
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return revisions, dbConn.InternalError(err)
 	}
 
 	return
 }
+
+// DeleteEditorRevisions drops the revisions of posts of other users that the
+// user replaced by editing them.
+func (s *storage) DeleteEditorRevisions(ctx context.Context, nickname string) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM post_revisions WHERE editor = $1", nickname)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package readMarkerStorage
+
+import (
+	"context"
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/EgorAist/TP_DB_project/internal/storages/dbConn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx"
+)
+
+type Storage interface {
+	MarkRead(ctx context.Context, input models.ReadMarker) (post int, err error)
+	CountUnread(ctx context.Context, nickname string, threads []int) (unread map[int]int, err error)
+	GetUserMarkers(ctx context.Context, nickname string) (markers []models.UserReadMarker, err error)
+	DeleteUser(ctx context.Context, nickname string) (err error)
+}
+
+type storage struct {
+	db *dbConn.DB
+}
+
+func NewStorage(db *dbConn.DB) Storage {
+	return &storage{
+		db: db,
+	}
+}
+
+func init() {
+	dbConn.RegisterQueries(map[string]string{
+		"markRead":    markRead,
+		"countUnread": countUnread,
+	})
+}
+
+// markRead moves the marker of the user forward to the post, or to the last
+// post of the thread when the post is 0. A marker never moves back.
+const markRead = `
+	INSERT INTO read_markers (user_nick, thread, post)
+	VALUES ($1, $2, CASE WHEN $3::INTEGER = 0 THEN (SELECT coalesce(max(ID), 0) FROM posts WHERE thread = $2) ELSE $3::INTEGER END)
+	ON CONFLICT (user_nick, thread) DO UPDATE
+	SET post = greatest(read_markers.post, EXCLUDED.post), updated = now()
+	RETURNING post
+`
+
+func (s *storage) MarkRead(ctx context.Context, input models.ReadMarker) (post int, err error) {
+	err = s.db.QueryRow(ctx, markRead, input.User, input.Thread, input.Post).Scan(&post)
+	if err != nil {
+		if pqErr, ok := err.(pgx.PgError); ok && pqErr.Code == pgerrcode.ForeignKeyViolation {
+			return 0, models.NewNotFound(dbConn.ReferencedEntity(pqErr))
+		}
+		return 0, dbConn.InternalError(err)
+	}
+	return
+}
+
+// countUnread counts, for each thread, the posts after the marker of the
+// user. Tombstones and the user's own posts are not unread. Each count is a
+// range scan of the (thread, ID) index, so a page of threads costs no more
+// than its unread posts however large the forum is.
+const countUnread = `
+	SELECT t.ID, (
+		SELECT count(*) FROM posts p
+		WHERE p.thread = t.ID AND p.ID > coalesce(m.post, 0) AND NOT p.deleted AND p.author <> $1::CITEXT
+	)::INTEGER
+	FROM unnest($2::INTEGER[]) t (ID)
+	LEFT JOIN read_markers m ON m.user_nick = $1::CITEXT AND m.thread = t.ID
+`
+
+func (s *storage) CountUnread(ctx context.Context, nickname string, threads []int) (unread map[int]int, err error) {
+	unread = make(map[int]int, len(threads))
+	if len(threads) == 0 {
+		return
+	}
+
+	rows, err := s.db.Query(ctx, countUnread, nickname, threads)
+	if err != nil {
+		return unread, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var thread, count int
+		if err = rows.Scan(&thread, &count); err != nil {
+			return unread, dbConn.InternalError(err)
+		}
+		unread[thread] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return unread, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) GetUserMarkers(ctx context.Context, nickname string) (markers []models.UserReadMarker, err error) {
+	rows, err := s.db.Query(ctx, "SELECT thread, post, updated FROM read_markers WHERE user_nick = $1 ORDER BY thread", nickname)
+	if err != nil {
+		return markers, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	markers = make([]models.UserReadMarker, 0)
+	for rows.Next() {
+		marker := models.UserReadMarker{}
+		if err = rows.Scan(&marker.Thread, &marker.Post, &marker.Updated); err != nil {
+			return markers, dbConn.InternalError(err)
+		}
+		markers = append(markers, marker)
+	}
+
+	if err = rows.Err(); err != nil {
+		return markers, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// DeleteUser drops the read markers of the user.
+func (s *storage) DeleteUser(ctx context.Context, nickname string) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM read_markers WHERE user_nick = $1", nickname)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
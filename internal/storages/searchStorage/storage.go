@@ -0,0 +1,106 @@
+package searchStorage
+
+import (
+	"context"
+	"github.com/EgorAist/TP_DB_project/internal/models"
+	"github.com/EgorAist/TP_DB_project/internal/storages/dbConn"
+)
+
+type Storage interface {
+	Search(ctx context.Context, input models.SearchInput) (hits []models.SearchHit, err error)
+}
+
+type storage struct {
+	db *dbConn.DB
+}
+
+func NewStorage(db *dbConn.DB) Storage {
+	return &storage{
+		db: db,
+	}
+}
+
+func init() {
+	dbConn.RegisterQueries(map[string]string{
+		"search": search,
+	})
+}
+
+// search ranks posts and threads matching $1 together. Empty filters match
+// everything; hits after the cursor ($9-$11) come ordered by rank, kind and
+// ID, so that pages never overlap.
+const search = `
+	WITH query AS (
+		SELECT plainto_tsquery('simple', $1::TEXT) AS q
+	), hits AS (
+		SELECT 'post'::TEXT AS kind, p.ID, p.thread, p.forum, p.author, p.created::TIMESTAMPTZ AS created,
+			''::TEXT AS title, p.message AS body, ts_rank(p.search, query.q) AS rank
+		FROM posts p JOIN threads t ON t.ID = p.thread, query
+		WHERE $7::BOOLEAN AND p.search @@ query.q AND NOT p.deleted AND t.state <> 'deleted'
+			AND ($2::CITEXT = '' OR p.forum = $2::CITEXT)
+			AND ($3::INTEGER = 0 OR p.thread = $3::INTEGER)
+			AND ($4::CITEXT = '' OR p.author = $4::CITEXT)
+			AND ($5::TIMESTAMPTZ IS NULL OR p.created::TIMESTAMPTZ >= $5::TIMESTAMPTZ)
+			AND ($6::TIMESTAMPTZ IS NULL OR p.created::TIMESTAMPTZ <= $6::TIMESTAMPTZ)
+		UNION ALL
+		SELECT 'thread'::TEXT, t.ID, t.ID, t.forum, t.author, t.created,
+			t.title, t.message, ts_rank(t.search, query.q)
+		FROM threads t, query
+		WHERE $8::BOOLEAN AND t.search @@ query.q AND t.state <> 'deleted'
+			AND ($2::CITEXT = '' OR t.forum = $2::CITEXT)
+			AND ($3::INTEGER = 0 OR t.ID = $3::INTEGER)
+			AND ($4::CITEXT = '' OR t.author = $4::CITEXT)
+			AND ($5::TIMESTAMPTZ IS NULL OR t.created >= $5::TIMESTAMPTZ)
+			AND ($6::TIMESTAMPTZ IS NULL OR t.created <= $6::TIMESTAMPTZ)
+	)
+	SELECT kind, ID, thread, forum, author, created, title,
+		ts_headline('simple', body, query.q, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10'),
+		rank
+	FROM hits, query
+	WHERE $9::REAL IS NULL OR (-rank, kind, ID) > (-$9::REAL, $10::TEXT, $11::INTEGER)
+	ORDER BY rank DESC, kind, ID
+	LIMIT $12
+`
+
+func (s *storage) Search(ctx context.Context, input models.SearchInput) (hits []models.SearchHit, err error) {
+	var since, until *string
+	if input.Since != "" {
+		since = &input.Since
+	}
+	if input.Until != "" {
+		until = &input.Until
+	}
+
+	var afterRank *float32
+	var afterKind string
+	var afterID int
+	if input.After != nil {
+		afterRank, afterKind, afterID = &input.After.Rank, input.After.Kind, input.After.ID
+	}
+
+	posts := input.Kind == "" || input.Kind == models.SearchPost
+	threads := input.Kind == "" || input.Kind == models.SearchThread
+
+	rows, err := s.db.Query(ctx, search, input.Query, input.Forum, input.Thread, input.Author, since, until,
+		posts, threads, afterRank, afterKind, afterID, input.Limit)
+	if err != nil {
+		return hits, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	hits = make([]models.SearchHit, 0)
+	for rows.Next() {
+		hit := models.SearchHit{}
+		err = rows.Scan(&hit.Kind, &hit.ID, &hit.Thread, &hit.Forum, &hit.Author, &hit.Created, &hit.Title, &hit.Snippet, &hit.Rank)
+		if err != nil {
+			return hits, dbConn.InternalError(err)
+		}
+		hits = append(hits, hit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return hits, dbConn.InternalError(err)
+	}
+
+	return
+}
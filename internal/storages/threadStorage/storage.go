@@ -1,144 +1,258 @@
 package threadStorage
 
 import (
+	"context"
 	"database/sql"
 	"github.com/jackc/pgerrcode"
+	"github.com/EgorAist/TP_DB_project/internal/storages/dbConn"
 	"github.com/jackc/pgx"
 	"github.com/EgorAist/TP_DB_project/internal/models"
 )
 
 type Storage interface {
-	CreateThread(input models.Thread) (thread models.Thread, err error)
-	GetDetails(input models.ThreadInput) (thread models.Thread, err error)
-	UpdateThread(input models.ThreadUpdate) (thread models.Thread, err error)
-	GetThreadsByForum(input models.ForumGetThreads) (threads []models.Thread, err error)
-	CheckThreadIfExists(input models.ThreadInput) (thread models.ThreadInput, err error)
-	GetThreadForPost(input models.ThreadInput, post *models.Thread) (err error)
-	GetForumByThread(input *models.ThreadInput) (forum string, err error)
+	CreateThread(ctx context.Context, input models.Thread) (thread models.Thread, err error)
+	GetDetails(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error)
+	UpdateThread(ctx context.Context, input models.ThreadUpdate) (thread models.Thread, err error)
+	GetThreadsByForum(ctx context.Context, input models.ForumGetThreads) (threads []models.Thread, err error)
+	GetThreadsByForums(ctx context.Context, input models.ForumGetThreads, forums []string) (threads []models.Thread, err error)
+	CheckThreadIfExists(ctx context.Context, input models.ThreadInput) (thread models.ThreadInput, err error)
+	GetThreadForPost(ctx context.Context, input models.ThreadInput, post *models.Thread) (err error)
+	GetForumByThread(ctx context.Context, input *models.ThreadInput) (forum string, state string, err error)
+	LockThread(ctx context.Context, input *models.ThreadInput) (forum string, state string, err error)
+	SetState(ctx context.Context, input models.ThreadState) (thread models.Thread, err error)
+	DeleteThread(ctx context.Context, input models.ThreadInput) (thread models.Thread, posts int, err error)
+	GetThreadsByAuthor(ctx context.Context, nickname string) (threads []models.Thread, err error)
+	GetThreadsByUser(ctx context.Context, input models.UserGetThreads) (threads []models.Thread, err error)
+	GetRevisions(ctx context.Context, input models.ThreadGetHistory) (revisions []models.ThreadRevision, err error)
+	SetTags(ctx context.Context, id int, tags []string) (err error)
+	GetThreadsByTag(ctx context.Context, input models.TagGetThreads) (threads []models.Thread, err error)
+	GetTags(ctx context.Context, input models.TagsGet) (tags []models.Tag, err error)
+	PurgeAuthorThreads(ctx context.Context, nickname string) (counters []models.ForumCounters, threads int, posts int, err error)
+	GetEditorRevisions(ctx context.Context, nickname string) (revisions []models.UserThreadRevision, err error)
+	DeleteEditorRevisions(ctx context.Context, nickname string) (err error)
 }
 
 type storage struct {
-	db *pgx.ConnPool
+	db *dbConn.DB
 
 }
 
-func NewStorage(db *pgx.ConnPool) Storage {
+func NewStorage(db *dbConn.DB) Storage {
 	return &storage{
 		db: db,
 	}
 }
 
-var (
-	insertWithSlug = "INSERT INTO threads (author, created, forum, message, slug, title, votes) VALUES ((SELECT u.nickname FROM users u WHERE u.nickname = $1), $2, (SELECT f.slug FROM forums f WHERE f.slug = $3), $4, $5, $6, $7) RETURNING ID, author, created, forum, message, slug, title, votes"
-	insertWithoutSlug = "INSERT INTO threads (author, created, forum, message, title, votes) VALUES ((SELECT u.nickname FROM users u WHERE u.nickname = $1), $2, (SELECT f.slug FROM forums f WHERE f.slug = $3), $4, $5, $6) RETURNING ID, author, created, forum, message, title, votes"
-
-	selectBySlug = "SELECT author, created, forum, ID, message, slug, title, votes FROM threads WHERE slug = $1"
-	selectByID = "SELECT author, created, forum, ID, message, slug, title, votes FROM threads WHERE ID = $1"
+func init() {
+	dbConn.RegisterQueries(map[string]string{
+		"insertWithSlug":         insertWithSlug,
+		"insertWithoutSlug":      insertWithoutSlug,
+		"selectBySlug":           selectBySlug,
+		"selectByID":             selectByID,
+		"selectThreads":          selectThreads,
+		"selectThreadsSince":     selectThreadsSince,
+		"selectThreadsDesc":      selectThreadsDesc,
+		"selectThreadsSinceDesc": selectThreadsSinceDesc,
+
+		"selectArchivedThreads":          selectArchivedThreads,
+		"selectArchivedThreadsSince":     selectArchivedThreadsSince,
+		"selectArchivedThreadsDesc":      selectArchivedThreadsDesc,
+		"selectArchivedThreadsSinceDesc": selectArchivedThreadsSinceDesc,
+		"deleteThread":                   deleteThread,
+		"purgeAuthorThreads":             purgeAuthorThreads,
+		"updateThreadWithRevision":       updateThreadWithRevision,
+		"selectUserThreads":              selectUserThreads,
+		"selectUserThreadsSince":         selectUserThreadsSince,
+		"selectUserThreadsDesc":          selectUserThreadsDesc,
+		"selectUserThreadsSinceDesc":     selectUserThreadsSinceDesc,
+		"selectTreeThreads":              selectTreeThreads,
+		"selectTreeThreadsSince":         selectTreeThreadsSince,
+		"selectTreeThreadsDesc":          selectTreeThreadsDesc,
+		"selectTreeThreadsSinceDesc":     selectTreeThreadsSinceDesc,
+		"selectTagThreads":               selectTagThreads,
+		"selectTagThreadsSince":          selectTagThreadsSince,
+		"selectTagThreadsDesc":           selectTagThreadsDesc,
+		"selectTagThreadsSinceDesc":      selectTagThreadsSinceDesc,
+	})
+}
 
-	selectThreads = "SELECT id, slug, author, created, forum, title, message, votes FROM threads WHERE forum = $1 ORDER BY created LIMIT $2"
-	selectThreadsSince = "SELECT id, slug, author, created, forum, title, message, votes FROM threads WHERE forum = $1 AND created >= $2 ORDER BY created LIMIT $3"
-	selectThreadsDesc = "SELECT id, slug, author, created, forum, title, message, votes FROM threads WHERE forum = $1 ORDER BY created DESC LIMIT $2"
-	selectThreadsSinceDesc =  "SELECT id, slug, author, created, forum, title, message, votes FROM threads WHERE forum = $1 AND created <= $2 ORDER BY created DESC LIMIT $3"
+var (
+	insertWithSlug = "INSERT INTO threads (author, created, forum, message, slug, title, votes, tags) VALUES ((SELECT u.nickname FROM users u WHERE u.nickname = $1), $2, (SELECT f.slug FROM forums f WHERE f.slug = $3), $4, $5, $6, $7, $8) RETURNING ID, author, created, forum, message, slug, title, votes, state, tags"
+	insertWithoutSlug = "INSERT INTO threads (author, created, forum, message, title, votes, tags) VALUES ((SELECT u.nickname FROM users u WHERE u.nickname = $1), $2, (SELECT f.slug FROM forums f WHERE f.slug = $3), $4, $5, $6, $7) RETURNING ID, author, created, forum, message, title, votes, state, tags"
+
+	selectBySlug = "SELECT author, created, forum, ID, message, slug, title, votes, state, edited_at, tags FROM threads WHERE slug = $1 AND state <> 'deleted'"
+	selectByID = "SELECT author, created, forum, ID, message, slug, title, votes, state, edited_at, tags FROM threads WHERE ID = $1 AND state <> 'deleted'"
+
+	// The state conditions repeat the predicates of the partial indexes on
+	// threads so that the planner can use them. Only threads with all the tags
+	// in the last parameter are listed; an empty array lists every thread.
+	// The default listings show archived threads too: each state set is read
+	// through its own partial index and the two ordered pages are merged.
+	selectThreads = "SELECT * FROM ((SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = $1 AND state IN ('open', 'locked') AND tags @> $3::TEXT[] ORDER BY created LIMIT $2) UNION ALL (SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = $1 AND state = 'archived' AND tags @> $3::TEXT[] ORDER BY created LIMIT $2)) t ORDER BY created LIMIT $2"
+	selectThreadsSince = "SELECT * FROM ((SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = $1 AND state IN ('open', 'locked') AND created >= $2 AND tags @> $4::TEXT[] ORDER BY created LIMIT $3) UNION ALL (SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = $1 AND state = 'archived' AND created >= $2 AND tags @> $4::TEXT[] ORDER BY created LIMIT $3)) t ORDER BY created LIMIT $3"
+	selectThreadsDesc = "SELECT * FROM ((SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = $1 AND state IN ('open', 'locked') AND tags @> $3::TEXT[] ORDER BY created DESC LIMIT $2) UNION ALL (SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = $1 AND state = 'archived' AND tags @> $3::TEXT[] ORDER BY created DESC LIMIT $2)) t ORDER BY created DESC LIMIT $2"
+	selectThreadsSinceDesc = "SELECT * FROM ((SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = $1 AND state IN ('open', 'locked') AND created <= $2 AND tags @> $4::TEXT[] ORDER BY created DESC LIMIT $3) UNION ALL (SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = $1 AND state = 'archived' AND created <= $2 AND tags @> $4::TEXT[] ORDER BY created DESC LIMIT $3)) t ORDER BY created DESC LIMIT $3"
+
+	selectArchivedThreads = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = $1 AND state = 'archived' AND tags @> $3::TEXT[] ORDER BY created LIMIT $2"
+	selectArchivedThreadsSince = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = $1 AND state = 'archived' AND created >= $2 AND tags @> $4::TEXT[] ORDER BY created LIMIT $3"
+	selectArchivedThreadsDesc = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = $1 AND state = 'archived' AND tags @> $3::TEXT[] ORDER BY created DESC LIMIT $2"
+	selectArchivedThreadsSinceDesc = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = $1 AND state = 'archived' AND created <= $2 AND tags @> $4::TEXT[] ORDER BY created DESC LIMIT $3"
+
+	// An empty forum ($2) lists the threads of the user in every forum.
+	selectUserThreads = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE author = $1 AND state <> 'deleted' AND ($2::CITEXT = '' OR forum = $2::CITEXT) ORDER BY created LIMIT $3"
+	selectUserThreadsSince = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE author = $1 AND state <> 'deleted' AND ($2::CITEXT = '' OR forum = $2::CITEXT) AND created >= $3 ORDER BY created LIMIT $4"
+	selectUserThreadsDesc = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE author = $1 AND state <> 'deleted' AND ($2::CITEXT = '' OR forum = $2::CITEXT) ORDER BY created DESC LIMIT $3"
+	selectUserThreadsSinceDesc = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE author = $1 AND state <> 'deleted' AND ($2::CITEXT = '' OR forum = $2::CITEXT) AND created <= $3 ORDER BY created DESC LIMIT $4"
+
+	// The threads of several forums at once, for a forum with its subforums.
+	// The states to list come in the last but one parameter.
+	selectTreeThreads = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = ANY($1::TEXT[]::CITEXT[]) AND state = ANY($3::TEXT[]) AND tags @> $4::TEXT[] ORDER BY created LIMIT $2"
+	selectTreeThreadsSince = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = ANY($1::TEXT[]::CITEXT[]) AND created >= $2 AND state = ANY($4::TEXT[]) AND tags @> $5::TEXT[] ORDER BY created LIMIT $3"
+	selectTreeThreadsDesc = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = ANY($1::TEXT[]::CITEXT[]) AND state = ANY($3::TEXT[]) AND tags @> $4::TEXT[] ORDER BY created DESC LIMIT $2"
+	selectTreeThreadsSinceDesc = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE forum = ANY($1::TEXT[]::CITEXT[]) AND created <= $2 AND state = ANY($4::TEXT[]) AND tags @> $5::TEXT[] ORDER BY created DESC LIMIT $3"
+
+	selectTagThreads = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE tags @> ARRAY[$1::TEXT] AND state <> 'deleted' ORDER BY created LIMIT $2"
+	selectTagThreadsSince = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE tags @> ARRAY[$1::TEXT] AND state <> 'deleted' AND created >= $2 ORDER BY created LIMIT $3"
+	selectTagThreadsDesc = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE tags @> ARRAY[$1::TEXT] AND state <> 'deleted' ORDER BY created DESC LIMIT $2"
+	selectTagThreadsSinceDesc = "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE tags @> ARRAY[$1::TEXT] AND state <> 'deleted' AND created <= $2 ORDER BY created DESC LIMIT $3"
+
+	// deleteThread also counts the live posts of the thread, which leave the
+	// forum counter with it.
+	deleteThread = "UPDATE threads SET state = 'deleted' WHERE (ID = $1 OR slug = $2) AND state <> 'deleted' " +
+		"RETURNING author, created, forum, ID, message, slug, title, votes, state, tags, " +
+		"(SELECT count(*) FROM posts p WHERE p.thread = threads.ID AND NOT p.deleted)"
 )
 
-func (s *storage) CreateThread(input models.Thread) (thread models.Thread, err error) {
+func (s *storage) CreateThread(ctx context.Context, input models.Thread) (thread models.Thread, err error) {
 	if input.Slug == "" {
-		err = s.db.QueryRow(insertWithoutSlug, input.Author, input.Created, input.Forum, input.Message, input.Title, input.Votes).
-					Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &thread.Title, &thread.Votes)
+		err = s.db.QueryRow(ctx, insertWithoutSlug, input.Author, input.Created, input.Forum, input.Message, input.Title, input.Votes, tagsOf(input.Tags)).
+					Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &thread.Title, &thread.Votes, &thread.State, &thread.Tags)
 	} else {
-		err = s.db.QueryRow(insertWithSlug, input.Author, input.Created, input.Forum, input.Message, input.Slug, input.Title, input.Votes).
-					Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &thread.Slug, &thread.Title, &thread.Votes)
+		err = s.db.QueryRow(ctx, insertWithSlug, input.Author, input.Created, input.Forum, input.Message, input.Slug, input.Title, input.Votes, tagsOf(input.Tags)).
+					Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &thread.Slug, &thread.Title, &thread.Votes, &thread.State, &thread.Tags)
 	}
 
 	if pqErr, ok := err.(pgx.PgError); ok {
 		switch pqErr.Code {
 		case pgerrcode.UniqueViolation:
-			return thread, models.Error{Code: "409"}
+			return thread, models.NewConflict(models.EntityThread, models.ReasonAlreadyExists, "thread already exists")
 		case pgerrcode.NotNullViolation, pgerrcode.ForeignKeyViolation:
-			return thread, models.Error{Code: "404"}
+			return thread, models.NewNotFound(dbConn.ReferencedEntity(pqErr))
 		default:
-			return thread, models.Error{Code: "500"}
+			return thread, dbConn.InternalError(err)
 		}
 	}
 
+	if err != nil {
+		return thread, dbConn.InternalError(err)
+	}
+
 	return
 }
 
-func (s *storage) GetDetails(input models.ThreadInput) (thread models.Thread, err error) {
+func (s *storage) GetDetails(ctx context.Context, input models.ThreadInput) (thread models.Thread, err error) {
 	slug := sql.NullString{}
 	if input.Slug == "" {
-		err = s.db.QueryRow(selectByID, input.ThreadID).
-					Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes)
+		err = s.db.QueryRow(ctx, selectByID, input.ThreadID).
+					Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.State, &thread.EditedAt, &thread.Tags)
 	} else {
-		err = s.db.QueryRow(selectBySlug, input.Slug).
-			Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes)
+		err = s.db.QueryRow(ctx, selectBySlug, input.Slug).
+			Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.State, &thread.EditedAt, &thread.Tags)
 	}
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return thread, models.Error{Code: "404"}
+			return thread, models.NewNotFound(models.EntityThread)
 
 		}
-		return thread, models.Error{Code: "500"}
+		return thread, dbConn.InternalError(err)
 	}
 
 	if slug.Valid {
 		thread.Slug = slug.String
 	}
+	thread.IsEdited = thread.EditedAt != nil
 
 	return
 }
 
-func (s *storage) UpdateThread(input models.ThreadUpdate) (thread models.Thread, err error) {
-	if input.Title != "" && input.Message != "" {
-		err = s.db.QueryRow("UPDATE threads SET message = $1, title = $2 WHERE ID = $3 OR slug = $4 " +
-								"RETURNING author, created, forum, ID, message, slug, title, votes",
-							input.Message, input.Title, input.ThreadID, input.Slug).
-					Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &thread.Slug, &thread.Title, &thread.Votes)
-
-	} else if input.Title != "" && input.Message == "" {
-		err = s.db.QueryRow("UPDATE threads SET title = $1 WHERE ID = $2 OR slug = $3 " +
-								"RETURNING author, created, forum, ID, message, slug, title, votes",
-								input.Title, input.ThreadID, input.Slug).
-					Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &thread.Slug, &thread.Title, &thread.Votes)
-
-	} else if input.Title == "" && input.Message != "" {
-		err = s.db.QueryRow("UPDATE threads SET message = $1 WHERE ID = $2 OR slug = $3 " +
-			"RETURNING author, created, forum, ID, message, slug, title, votes",
-			input.Message, input.ThreadID, input.Slug).
-			Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &thread.Slug, &thread.Title, &thread.Votes)
-
+// updateThreadWithRevision records the change as the next revision of the
+// thread; the fields left alone are NULL in it. The editor defaults to the
+// author.
+const updateThreadWithRevision = `
+	WITH revision AS (
+		INSERT INTO thread_revisions (thread, revision, old_title, new_title, old_message, new_message, editor)
+		SELECT t.ID, coalesce((SELECT max(r.revision) FROM thread_revisions r WHERE r.thread = t.ID), 0) + 1,
+			$2, $3, $4, $5, coalesce(NULLIF($6, '')::CITEXT, t.author)
+		FROM threads t WHERE t.ID = $1
+	)
+	UPDATE threads SET title = coalesce($3, title), message = coalesce($5, message), edited_at = now()
+	WHERE ID = $1
+	RETURNING author, created, forum, ID, message, slug, title, votes, state, edited_at, tags
+`
+
+// UpdateThread must run inside a unit of work: the thread is locked until the
+// revision of the change is stored. An update that changes nothing is not
+// recorded.
+func (s *storage) UpdateThread(ctx context.Context, input models.ThreadUpdate) (thread models.Thread, err error) {
+	var id int
+	var title, message string
+	err = s.db.QueryRow(ctx, "SELECT ID, title, message FROM threads WHERE (ID = $1 OR slug = $2) AND state <> 'deleted' FOR UPDATE",
+		input.ThreadID, input.Slug).
+		Scan(&id, &title, &message)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return thread, models.NewNotFound(models.EntityThread)
+		}
+		return thread, dbConn.InternalError(err)
+	}
 
-	} else if input.Title == "" && input.Message == "" {
-		err = s.db.QueryRow("SELECT author, created, forum, ID, message, slug, title, votes FROM threads WHERE ID = $1 OR slug = $2", input.ThreadID, input.Slug).
-					Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &thread.Slug, &thread.Title, &thread.Votes)
+	var oldTitle, newTitle, oldMessage, newMessage *string
+	if input.Title != "" && input.Title != title {
+		oldTitle, newTitle = &title, &input.Title
+	}
+	if input.Message != "" && input.Message != message {
+		oldMessage, newMessage = &message, &input.Message
+	}
+	if newTitle == nil && newMessage == nil {
+		return s.GetDetails(ctx, models.ThreadInput{ThreadID: id})
 	}
 
+	slug := sql.NullString{}
+	err = s.db.QueryRow(ctx, updateThreadWithRevision, id, oldTitle, newTitle, oldMessage, newMessage, input.Editor).
+		Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.State, &thread.EditedAt, &thread.Tags)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return thread, models.Error{Code: "404"}
-
+		if pqErr, ok := err.(pgx.PgError); ok && pqErr.Code == pgerrcode.ForeignKeyViolation {
+			return thread, models.NewNotFound(models.EntityUser)
 		}
-		return thread, models.Error{Code: "500"}
+		return thread, dbConn.InternalError(err)
 	}
 
+	if slug.Valid {
+		thread.Slug = slug.String
+	}
+	thread.IsEdited = true
+
 	return
 }
 
-func (s *storage) GetThreadsByForum(input models.ForumGetThreads) (threads []models.Thread, err error) {
-	var rows *pgx.Rows
-	if input.Since == "" && !input.Desc {
-		rows, err = s.db.Query(selectThreads, input.Slug, input.Limit)
+func (s *storage) GetThreadsByForum(ctx context.Context, input models.ForumGetThreads) (threads []models.Thread, err error) {
+	var rows *dbConn.Rows
+	tags := tagsOf(input.Tags)
+	if input.Archived {
+		rows, err = s.getArchivedThreads(ctx, input, tags)
+	} else if input.Since == "" && !input.Desc {
+		rows, err = s.db.Query(ctx, selectThreads, input.Slug, input.Limit, tags)
 	} else if input.Since == "" && input.Desc {
-		rows, err = s.db.Query(selectThreadsDesc,  input.Slug, input.Limit)
+		rows, err = s.db.Query(ctx, selectThreadsDesc,  input.Slug, input.Limit, tags)
 	}  else if input.Since != "" && !input.Desc {
-		rows, err = s.db.Query(selectThreadsSince,  input.Slug, input.Since, input.Limit)
+		rows, err = s.db.Query(ctx, selectThreadsSince,  input.Slug, input.Since, input.Limit, tags)
 	} else if input.Since != "" && input.Desc {
-		rows, err = s.db.Query(selectThreadsSinceDesc, input.Slug, input.Since, input.Limit)
+		rows, err = s.db.Query(ctx, selectThreadsSinceDesc, input.Slug, input.Since, input.Limit, tags)
 	}
 
 	if err != nil {
-		return threads, models.Error{Code: "500"}
+		return threads, dbConn.InternalError(err)
 	}
 	defer rows.Close()
 
@@ -147,9 +261,9 @@ func (s *storage) GetThreadsByForum(input models.ForumGetThreads) (threads []mod
 		thread := models.Thread{}
 		slug := sql.NullString{}
 
-		err = rows.Scan(&thread.ID, &slug, &thread.Author, &thread.Created, &thread.Forum, &thread.Title, &thread.Message, &thread.Votes)
+		err = rows.Scan(&thread.ID, &slug, &thread.Author, &thread.Created, &thread.Forum, &thread.Title, &thread.Message, &thread.Votes, &thread.State, &thread.Tags)
 		if err != nil {
-			return threads, models.Error{Code: "500"}
+			return threads, dbConn.InternalError(err)
 		}
 
 		if slug.Valid {
@@ -159,54 +273,411 @@ func (s *storage) GetThreadsByForum(input models.ForumGetThreads) (threads []mod
 		threads = append(threads, thread)
 	}
 
+	if err = rows.Err(); err != nil {
+		return threads, dbConn.InternalError(err)
+	}
+
 	return
 }
 
-func (s storage) CheckThreadIfExists(input models.ThreadInput) (thread models.ThreadInput, err error) {
+// GetThreadsByForums lists the threads of all the forums together, with the
+// same filters and order as GetThreadsByForum.
+func (s *storage) GetThreadsByForums(ctx context.Context, input models.ForumGetThreads, forums []string) (threads []models.Thread, err error) {
+	states := []string{models.ThreadOpen, models.ThreadLocked, models.ThreadArchived}
+	if input.Archived {
+		states = []string{models.ThreadArchived}
+	}
+	tags := tagsOf(input.Tags)
+
+	var rows *dbConn.Rows
+	if input.Since == "" && !input.Desc {
+		rows, err = s.db.Query(ctx, selectTreeThreads, forums, input.Limit, states, tags)
+	} else if input.Since == "" && input.Desc {
+		rows, err = s.db.Query(ctx, selectTreeThreadsDesc, forums, input.Limit, states, tags)
+	} else if input.Since != "" && !input.Desc {
+		rows, err = s.db.Query(ctx, selectTreeThreadsSince, forums, input.Since, input.Limit, states, tags)
+	} else {
+		rows, err = s.db.Query(ctx, selectTreeThreadsSinceDesc, forums, input.Since, input.Limit, states, tags)
+	}
+	if err != nil {
+		return threads, dbConn.InternalError(err)
+	}
+	return scanThreads(rows)
+}
+
+func (s *storage) getArchivedThreads(ctx context.Context, input models.ForumGetThreads, tags []string) (*dbConn.Rows, error) {
+	switch {
+	case input.Since == "" && !input.Desc:
+		return s.db.Query(ctx, selectArchivedThreads, input.Slug, input.Limit, tags)
+	case input.Since == "":
+		return s.db.Query(ctx, selectArchivedThreadsDesc, input.Slug, input.Limit, tags)
+	case !input.Desc:
+		return s.db.Query(ctx, selectArchivedThreadsSince, input.Slug, input.Since, input.Limit, tags)
+	default:
+		return s.db.Query(ctx, selectArchivedThreadsSinceDesc, input.Slug, input.Since, input.Limit, tags)
+	}
+}
+
+// tagsOf returns the tags as a non-nil slice, since a nil one is sent as NULL
+// and NULL matches no thread.
+func tagsOf(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+func (s storage) CheckThreadIfExists(ctx context.Context, input models.ThreadInput) (thread models.ThreadInput, err error) {
 	if input.Slug == "" {
-		err = s.db.QueryRow("SELECT ID from threads WHERE ID = $1", input.ThreadID).Scan(&thread.ThreadID)
+		err = s.db.QueryRow(ctx, "SELECT ID from threads WHERE ID = $1 AND state <> 'deleted'", input.ThreadID).Scan(&thread.ThreadID)
 	} else {
-		err = s.db.QueryRow("SELECT ID from threads WHERE slug = $1", input.Slug).Scan(&thread.ThreadID)
+		err = s.db.QueryRow(ctx, "SELECT ID from threads WHERE slug = $1 AND state <> 'deleted'", input.Slug).Scan(&thread.ThreadID)
 	}
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return thread, models.Error{Code: "404"}
+			return thread, models.NewNotFound(models.EntityThread)
 		}
-		return thread, models.Error{Code: "500"}
+		return thread, dbConn.InternalError(err)
 	}
 
 	return
 }
 
-func (s *storage) GetThreadForPost(input models.ThreadInput, thread *models.Thread) (err error) {
+func (s *storage) GetThreadForPost(ctx context.Context, input models.ThreadInput, thread *models.Thread) (err error) {
 	slug := sql.NullString{}
-	err = s.db.QueryRow(selectByID, input.ThreadID).
-				Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes)
+	err = s.db.QueryRow(ctx, selectByID, input.ThreadID).
+				Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.State, &thread.EditedAt, &thread.Tags)
 
 	if err != nil {
-		return models.Error{Code: "500"}
+		return dbConn.InternalError(err)
 	}
 
 	if slug.Valid {
 		thread.Slug = slug.String
 	}
+	thread.IsEdited = thread.EditedAt != nil
+
+	return
+}
+
+func (s *storage) GetForumByThread(ctx context.Context, input *models.ThreadInput) (forum string, state string, err error) {
+	if input.Slug == "" {
+		err = s.db.QueryRow(ctx, "SELECT forum, state FROM threads WHERE ID = $1 AND state <> 'deleted'", input.ThreadID).Scan(&forum, &state)
+	} else {
+		err = s.db.QueryRow(ctx, "SELECT forum, state, ID FROM threads WHERE slug = $1 AND state <> 'deleted'", input.Slug).Scan(&forum, &state, &input.ThreadID)
+	}
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return forum, state, models.NewNotFound(models.EntityThread)
+		}
+
+		return forum, state, dbConn.InternalError(err)
+	}
 
 	return
 }
 
-func (s *storage) GetForumByThread(input *models.ThreadInput) (forum string, err error) {
+// LockThread is GetForumByThread that also locks the thread row until the
+// unit of work ends, so votes on one thread take their turns.
+func (s *storage) LockThread(ctx context.Context, input *models.ThreadInput) (forum string, state string, err error) {
 	if input.Slug == "" {
-		err = s.db.QueryRow("SELECT forum FROM threads WHERE ID = $1", input.ThreadID).Scan(&forum)
+		err = s.db.QueryRow(ctx, "SELECT forum, state FROM threads WHERE ID = $1 AND state <> 'deleted' FOR UPDATE", input.ThreadID).Scan(&forum, &state)
 	} else {
-		err = s.db.QueryRow("SELECT forum, ID FROM threads WHERE slug = $1", input.Slug).Scan(&forum, &input.ThreadID)
+		err = s.db.QueryRow(ctx, "SELECT forum, state, ID FROM threads WHERE slug = $1 AND state <> 'deleted' FOR UPDATE", input.Slug).Scan(&forum, &state, &input.ThreadID)
 	}
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return forum, models.Error{Code: "404"}
+			return forum, state, models.NewNotFound(models.EntityThread)
+		}
+
+		return forum, state, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) SetState(ctx context.Context, input models.ThreadState) (thread models.Thread, err error) {
+	slug := sql.NullString{}
+	err = s.db.QueryRow(ctx, "UPDATE threads SET state = $1 WHERE (ID = $2 OR slug = $3) AND state <> 'deleted' " +
+							"RETURNING author, created, forum, ID, message, slug, title, votes, state, tags",
+						input.State, input.ThreadID, input.Slug).
+				Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.State, &thread.Tags)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return thread, models.NewNotFound(models.EntityThread)
+		}
+		return thread, dbConn.InternalError(err)
+	}
+
+	if slug.Valid {
+		thread.Slug = slug.String
+	}
+
+	return
+}
+
+// DeleteThread marks the thread deleted and returns it along with the number
+// of its posts that are not tombstones.
+func (s *storage) DeleteThread(ctx context.Context, input models.ThreadInput) (thread models.Thread, posts int, err error) {
+	slug := sql.NullString{}
+	err = s.db.QueryRow(ctx, deleteThread, input.ThreadID, input.Slug).
+				Scan(&thread.Author, &thread.Created, &thread.Forum, &thread.ID, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.State, &thread.Tags, &posts)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return thread, 0, models.NewNotFound(models.EntityThread)
+		}
+		return thread, 0, dbConn.InternalError(err)
+	}
+
+	if slug.Valid {
+		thread.Slug = slug.String
+	}
+
+	return
+}
+
+func (s *storage) GetThreadsByAuthor(ctx context.Context, nickname string) (threads []models.Thread, err error) {
+	rows, err := s.db.Query(ctx, "SELECT id, slug, author, created, forum, title, message, votes, state, tags FROM threads WHERE author = $1 ORDER BY created, id", nickname)
+	if err != nil {
+		return threads, dbConn.InternalError(err)
+	}
+	return scanThreads(rows)
+}
+
+// GetThreadsByUser lists the threads the user has started, in any forum
+// unless input.Forum is set.
+func (s *storage) GetThreadsByUser(ctx context.Context, input models.UserGetThreads) (threads []models.Thread, err error) {
+	var rows *dbConn.Rows
+	if input.Since == "" && !input.Desc {
+		rows, err = s.db.Query(ctx, selectUserThreads, input.Nickname, input.Forum, input.Limit)
+	} else if input.Since == "" && input.Desc {
+		rows, err = s.db.Query(ctx, selectUserThreadsDesc, input.Nickname, input.Forum, input.Limit)
+	} else if input.Since != "" && !input.Desc {
+		rows, err = s.db.Query(ctx, selectUserThreadsSince, input.Nickname, input.Forum, input.Since, input.Limit)
+	} else {
+		rows, err = s.db.Query(ctx, selectUserThreadsSinceDesc, input.Nickname, input.Forum, input.Since, input.Limit)
+	}
+	if err != nil {
+		return threads, dbConn.InternalError(err)
+	}
+	return scanThreads(rows)
+}
+
+// SetTags replaces the tags of the thread; the dictionary follows through the
+// thread_tags_count trigger.
+func (s *storage) SetTags(ctx context.Context, id int, tags []string) (err error) {
+	_, err = s.db.Exec(ctx, "UPDATE threads SET tags = $2 WHERE ID = $1", id, tagsOf(tags))
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
+
+// GetThreadsByTag lists the threads with the tag in every forum, deleted ones
+// aside, the same way GetThreadsByForum lists those of a forum.
+func (s *storage) GetThreadsByTag(ctx context.Context, input models.TagGetThreads) (threads []models.Thread, err error) {
+	var rows *dbConn.Rows
+	if input.Since == "" && !input.Desc {
+		rows, err = s.db.Query(ctx, selectTagThreads, input.Tag, input.Limit)
+	} else if input.Since == "" && input.Desc {
+		rows, err = s.db.Query(ctx, selectTagThreadsDesc, input.Tag, input.Limit)
+	} else if input.Since != "" && !input.Desc {
+		rows, err = s.db.Query(ctx, selectTagThreadsSince, input.Tag, input.Since, input.Limit)
+	} else {
+		rows, err = s.db.Query(ctx, selectTagThreadsSinceDesc, input.Tag, input.Since, input.Limit)
+	}
+	if err != nil {
+		return threads, dbConn.InternalError(err)
+	}
+	return scanThreads(rows)
+}
+
+// GetTags lists the tags in use, the most used first. Tags starting with the
+// prefix are compared with left() rather than LIKE, as '_' is allowed in tags.
+func (s *storage) GetTags(ctx context.Context, input models.TagsGet) (tags []models.Tag, err error) {
+	rows, err := s.db.Query(ctx, "SELECT name, threads FROM tags WHERE threads > 0 AND left(name, char_length($1)) = $1 "+
+		"ORDER BY threads DESC, name LIMIT $2", input.Prefix, input.Limit)
+	if err != nil {
+		return tags, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	tags = make([]models.Tag, 0)
+	for rows.Next() {
+		tag := models.Tag{}
+		if err = rows.Scan(&tag.Name, &tag.Threads); err != nil {
+			return tags, dbConn.InternalError(err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return tags, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func scanThreads(rows *dbConn.Rows) (threads []models.Thread, err error) {
+	defer rows.Close()
+
+	threads = make([]models.Thread, 0)
+	for rows.Next() {
+		thread := models.Thread{}
+		slug := sql.NullString{}
+
+		err = rows.Scan(&thread.ID, &slug, &thread.Author, &thread.Created, &thread.Forum, &thread.Title, &thread.Message, &thread.Votes, &thread.State, &thread.Tags)
+		if err != nil {
+			return threads, dbConn.InternalError(err)
+		}
+
+		if slug.Valid {
+			thread.Slug = slug.String
+		}
+
+		threads = append(threads, thread)
+	}
+
+	if err = rows.Err(); err != nil {
+		return threads, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// purgeAuthorThreads removes the threads of a user with all their posts and
+// votes. Per forum it returns the number of removed threads and posts, and of
+// those that were still counted in forums: threads not deleted before and
+// their posts that were not tombstones.
+const purgeAuthorThreads = `
+	WITH doomed AS (
+		SELECT ID, forum, state <> 'deleted' AS live FROM threads WHERE author = $1
+	), purged_votes AS (
+		DELETE FROM votes v USING doomed d WHERE v.thread = d.ID
+	), purged_posts AS (
+		DELETE FROM posts p USING doomed d WHERE p.thread = d.ID
+		RETURNING p.thread, d.live AND NOT p.deleted AS live
+	), purged_threads AS (
+		DELETE FROM threads t USING doomed d WHERE t.ID = d.ID
+	)
+	SELECT d.forum,
+		count(*)::INTEGER,
+		(count(*) FILTER (WHERE d.live))::INTEGER,
+		coalesce(sum(pp.posts), 0)::INTEGER,
+		coalesce(sum(pp.live), 0)::INTEGER
+	FROM doomed d
+	LEFT JOIN (
+		SELECT thread, count(*) AS posts, count(*) FILTER (WHERE live) AS live FROM purged_posts GROUP BY thread
+	) pp ON pp.thread = d.ID
+	GROUP BY d.forum
+`
+
+// PurgeAuthorThreads removes every thread of the user along with its posts and
+// votes. The counters tell how much to take off each forum.
+func (s *storage) PurgeAuthorThreads(ctx context.Context, nickname string) (counters []models.ForumCounters, threads int, posts int, err error) {
+	rows, err := s.db.Query(ctx, purgeAuthorThreads, nickname)
+	if err != nil {
+		return nil, 0, 0, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var counter models.ForumCounters
+		var forumThreads, forumPosts int
+		if err = rows.Scan(&counter.Forum, &forumThreads, &counter.Threads, &forumPosts, &counter.Posts); err != nil {
+			return nil, 0, 0, dbConn.InternalError(err)
 		}
+		threads += forumThreads
+		posts += forumPosts
+		counters = append(counters, counter)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, 0, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// GetEditorRevisions lists the changes the user made to threads.
+func (s *storage) GetEditorRevisions(ctx context.Context, nickname string) (revisions []models.UserThreadRevision, err error) {
+	rows, err := s.db.Query(ctx, "SELECT thread, revision, old_title, new_title, old_message, new_message, edited FROM thread_revisions WHERE editor = $1 ORDER BY thread, revision", nickname)
+	if err != nil {
+		return revisions, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	revisions = make([]models.UserThreadRevision, 0)
+	for rows.Next() {
+		revision := models.UserThreadRevision{}
+		var oldTitle, newTitle, oldMessage, newMessage sql.NullString
+		err = rows.Scan(&revision.Thread, &revision.Revision, &oldTitle, &newTitle, &oldMessage, &newMessage, &revision.Edited)
+		if err != nil {
+			return revisions, dbConn.InternalError(err)
+		}
+
+		if newTitle.Valid {
+			revision.Title = &models.FieldChange{Old: oldTitle.String, New: newTitle.String}
+		}
+		if newMessage.Valid {
+			revision.Message = &models.FieldChange{Old: oldMessage.String, New: newMessage.String}
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return revisions, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// DeleteEditorRevisions drops the changes the user made to threads of other
+// users, old and new text alike.
+func (s *storage) DeleteEditorRevisions(ctx context.Context, nickname string) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM thread_revisions WHERE editor = $1", nickname)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+	return
+}
+
+// GetRevisions lists the changes of the thread; input.ThreadID must be set.
+func (s *storage) GetRevisions(ctx context.Context, input models.ThreadGetHistory) (revisions []models.ThreadRevision, err error) {
+	query := "SELECT revision, old_title, new_title, old_message, new_message, editor, edited FROM thread_revisions WHERE thread = $1 ORDER BY revision LIMIT $2"
+	if input.Desc {
+		query = "SELECT revision, old_title, new_title, old_message, new_message, editor, edited FROM thread_revisions WHERE thread = $1 ORDER BY revision DESC LIMIT $2"
+	}
+
+	rows, err := s.db.Query(ctx, query, input.ThreadID, input.Limit)
+	if err != nil {
+		return revisions, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	revisions = make([]models.ThreadRevision, 0)
+	for rows.Next() {
+		revision := models.ThreadRevision{}
+		var oldTitle, newTitle, oldMessage, newMessage sql.NullString
+		err = rows.Scan(&revision.Revision, &oldTitle, &newTitle, &oldMessage, &newMessage, &revision.Editor, &revision.Edited)
+		if err != nil {
+			return revisions, dbConn.InternalError(err)
+		}
+
+		if newTitle.Valid {
+			revision.Title = &models.FieldChange{Old: oldTitle.String, New: newTitle.String}
+		}
+		if newMessage.Valid {
+			revision.Message = &models.FieldChange{Old: oldMessage.String, New: newMessage.String}
+		}
+
+		revisions = append(revisions, revision)
+	}
 
-		return forum, models.Error{Code: "500"}
+	if err = rows.Err(); err != nil {
+		return revisions, dbConn.InternalError(err)
 	}
 
 	return
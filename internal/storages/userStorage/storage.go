@@ -1,35 +1,62 @@
 package userStorage
 
 import (
-	"fmt"
+	"context"
 	//"fmt"
 	"github.com/jackc/pgerrcode"
+	"github.com/EgorAist/TP_DB_project/internal/storages/dbConn"
 	"github.com/jackc/pgx"
 	"github.com/EgorAist/TP_DB_project/internal/models"
+	"time"
 )
 
 type Storage interface {
-	CreateUser(input models.User) (user models.User, err error)
-	GetProfile(input string) (user models.User, err error)
-	UpdateProfile(input models.User) (user models.User, err error)
-	GetUsers(input models.ForumGetUsers, forum string) (users []models.User, err error)
-
-	GetUserForPost(input string,  user *models.User) (err error)
-	GetUserIDByNickname(input string) (userID int, err error)
-	GetUserByNickname(input string) (nickname string, err error)
-	GetEmailConflictUser(email string) (user models.User, err error)
+	CreateUser(ctx context.Context, input models.User) (user models.User, err error)
+	GetProfile(ctx context.Context, input string) (user models.User, err error)
+	UpdateProfile(ctx context.Context, input models.User) (user models.User, err error)
+	GetUsers(ctx context.Context, input models.ForumGetUsers, forum string) (users []models.User, err error)
+
+	GetUserForPost(ctx context.Context, input string,  user *models.User) (err error)
+	GetUserIDByNickname(ctx context.Context, input string) (userID int, err error)
+	GetUserByNickname(ctx context.Context, input string) (nickname string, err error)
+	GetEmailConflictUser(ctx context.Context, email string) (user models.User, err error)
+
+	RenameUser(ctx context.Context, input models.UserRename) (user models.User, err error)
+	ResolveAlias(ctx context.Context, alias string) (nickname string, err error)
+	AddAlias(ctx context.Context, alias string, nickname string, reservedUntil time.Time) (err error)
+	RemoveAlias(ctx context.Context, alias string) (err error)
+	GetAliases(ctx context.Context, nickname string) (aliases []string, err error)
+	AnonymizeUser(ctx context.Context, nickname string) (pseudonym string, err error)
+	GetCounters(ctx context.Context, nickname string) (counters models.UserCounters, err error)
 }
 
 type storage struct {
-	db *pgx.ConnPool
+	db *dbConn.DB
 }
 
-func NewStorage(db *pgx.ConnPool) Storage {
+func NewStorage(db *dbConn.DB) Storage {
 	return &storage{
 		db: db,
 	}
 }
 
+func init() {
+	dbConn.RegisterQueries(map[string]string{
+		"selectEmpty":         selectEmpty,
+		"selectWithSince":     selectWithSince,
+		"selectWithDesc":      selectWithDesc,
+		"selectWithSinceDesc": selectWithSinceDesc,
+		"updateFull":          updateFull,
+		"updateEmail":         updateEmail,
+		"updateFullname":      updateFullname,
+		"updateAbout":         updateAbout,
+		"updateEmailFullname": updateEmailFullname,
+		"updateEmailAbout":    updateEmailAbout,
+		"updateFullnameAbout": updateFullnameAbout,
+		"renameUser":          renameUser,
+	})
+}
+
 var (
 
 	selectEmpty = "SELECT u.nickname, u.fullname, u.about, u.email FROM forum_users fu JOIN users u ON fu.nickname = u.nickname WHERE fu.forum = $1 ORDER BY u.nickname LIMIT $2"
@@ -37,43 +64,48 @@ var (
 	selectWithDesc = "SELECT u.nickname, u.fullname, u.about, u.email FROM forum_users fu JOIN users u ON fu.nickname = u.nickname WHERE fu.forum = $1 ORDER BY u.nickname DESC LIMIT $2"
 	selectWithSinceDesc =  "SELECT u.nickname, u.fullname, u.about, u.email FROM forum_users fu JOIN users u ON fu.nickname = u.nickname WHERE fu.forum = $1 AND u.nickname < $2 ORDER BY u.nickname DESC LIMIT $3"
 
-	updateFull = "UPDATE users SET nickname = $1, fullname = $2, email = $3, about = $4 WHERE nickname = $5 RETURNING fullname, email, about, nickname"
-	updateEmail = "UPDATE users SET nickname = $1, email = $2 WHERE nickname = $3 RETURNING fullname, email, about, nickname"
-	updateFullname = "UPDATE users SET nickname = $1, fullname = $2 WHERE nickname = $3 RETURNING fullname, email, about, nickname"
-	updateAbout = "UPDATE users SET nickname = $1, about = $2 WHERE nickname = $3 RETURNING fullname, email, about, nickname"
-	updateEmailFullname = "UPDATE users SET nickname = $1, fullname = $2, email = $3 WHERE nickname = $4 RETURNING fullname, email, about, nickname"
-	updateEmailAbout = "UPDATE users SET nickname = $1, email = $2, about = $3 WHERE nickname = $4 RETURNING fullname, email, about, nickname"
-	updateFullnameAbout = "UPDATE users SET nickname = $1, fullname = $2, about = $3 WHERE nickname = $4 RETURNING fullname, email, about, nickname"
+	updateFull = "UPDATE users SET fullname = $1, email = $2, about = $3 WHERE nickname = $4 RETURNING fullname, email, about, nickname"
+	updateEmail = "UPDATE users SET email = $1 WHERE nickname = $2 RETURNING fullname, email, about, nickname"
+	updateFullname = "UPDATE users SET fullname = $1 WHERE nickname = $2 RETURNING fullname, email, about, nickname"
+	updateAbout = "UPDATE users SET about = $1 WHERE nickname = $2 RETURNING fullname, email, about, nickname"
+	updateEmailFullname = "UPDATE users SET fullname = $1, email = $2 WHERE nickname = $3 RETURNING fullname, email, about, nickname"
+	updateEmailAbout = "UPDATE users SET email = $1, about = $2 WHERE nickname = $3 RETURNING fullname, email, about, nickname"
+	updateFullnameAbout = "UPDATE users SET fullname = $1, about = $2 WHERE nickname = $3 RETURNING fullname, email, about, nickname"
+
+	// The nickname is carried over to forums, threads, posts, votes,
+	// forum_users and user_aliases by their foreign keys.
+	renameUser = "UPDATE users SET nickname = $2 WHERE nickname = $1 RETURNING fullname, email, about, nickname"
 )
 
-func (s storage) GetUserByNickname(input string) (nickname string, err error) {
-	err = s.db.QueryRow("SELECT nickname FROM users WHERE nickname = $1", input).Scan(&nickname)
+func (s storage) GetUserByNickname(ctx context.Context, input string) (nickname string, err error) {
+	err = s.db.QueryRow(ctx, "SELECT nickname FROM users WHERE nickname = $1", input).Scan(&nickname)
 	if err != nil {
-		if pgerr, ok := err.(pgx.PgError); ok {
-			if pgerr == pgx.ErrNoRows {
-				return nickname, models.Error{Code: "404"}
-
-			}
+		if err == pgx.ErrNoRows {
+			return nickname, models.NewNotFound(models.EntityUser)
 		}
-		return nickname, models.Error{Code: "500"}
+		return nickname, dbConn.InternalError(err)
 	}
 
 	return
 }
 
-func (s *storage) CreateUser(input models.User) (user models.User, err error) {
-	_, err = s.db.Exec("INSERT INTO users (nickname, email, fullname, about) VALUES ($1, $2, $3, $4)",
+func (s *storage) CreateUser(ctx context.Context, input models.User) (user models.User, err error) {
+	_, err = s.db.Exec(ctx, "INSERT INTO users (nickname, email, fullname, about) VALUES ($1, $2, $3, $4)",
 		input.Nickname, input.Email, input.Fullname, input.About)
 
 	if pqErr, ok := err.(pgx.PgError); ok {
 		switch pqErr.Code {
 		case pgerrcode.UniqueViolation:
-			return user, models.Error{Code: "409", Message: "conflict user"}
+			return user, models.NewConflict(models.EntityUser, models.ReasonAlreadyExists, "user already exists")
 		default:
-			return user, models.Error{Code: "500"}
+			return user, dbConn.InternalError(err)
 		}
 	}
 
+	if err != nil {
+		return user, dbConn.InternalError(err)
+	}
+
 	user.Nickname = input.Nickname
 	user.Fullname = input.Fullname
 	user.Email = input.Email
@@ -82,79 +114,82 @@ func (s *storage) CreateUser(input models.User) (user models.User, err error) {
 	return
 }
 
-func (s *storage) GetProfile(input string) (user models.User, err error) {
-	err = s.db.QueryRow("SELECT fullname, email, about, nickname FROM users WHERE nickname = $1", input).
+func (s *storage) GetProfile(ctx context.Context, input string) (user models.User, err error) {
+	err = s.db.QueryRow(ctx, "SELECT fullname, email, about, nickname FROM users WHERE nickname = $1", input).
 		Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return user, models.Error{Code: "404"}
+			return user, models.NewNotFound(models.EntityUser)
 
 		}
-		return user, models.Error{Code: "500"}
+		return user, dbConn.InternalError(err)
 	}
 
 	return
 }
 
-func (s *storage) UpdateProfile(input models.User) (user models.User, err error) {
+func (s *storage) UpdateProfile(ctx context.Context, input models.User) (user models.User, err error) {
 	if input.About != "" && input.Email != "" && input.Fullname != "" {
-		err = s.db.QueryRow(updateFull, input.Nickname, input.Fullname, input.Email, input.About, input.Nickname).
+		err = s.db.QueryRow(ctx, updateFull, input.Fullname, input.Email, input.About, input.Nickname).
 			Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	} else if input.About != "" && input.Email != "" {
-		err = s.db.QueryRow(updateEmailAbout, input.Nickname, input.Email, input.About, input.Nickname).
+		err = s.db.QueryRow(ctx, updateEmailAbout, input.Email, input.About, input.Nickname).
 			Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	} else if input.Email != "" && input.Fullname != "" {
-		err = s.db.QueryRow(updateEmailFullname, input.Nickname, input.Fullname, input.Email, input.Nickname).
+		err = s.db.QueryRow(ctx, updateEmailFullname, input.Fullname, input.Email, input.Nickname).
 			Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	} else if input.About != "" && input.Fullname != "" {
-		err = s.db.QueryRow(updateFullnameAbout, input.Nickname, input.Fullname, input.About, input.Nickname).
+		err = s.db.QueryRow(ctx, updateFullnameAbout, input.Fullname, input.About, input.Nickname).
 			Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	} else if input.About != "" {
-		err = s.db.QueryRow(updateAbout, input.Nickname, input.About, input.Nickname).
+		err = s.db.QueryRow(ctx, updateAbout, input.About, input.Nickname).
 			Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	} else if input.Fullname != "" {
-		err = s.db.QueryRow(updateFullname, input.Nickname, input.Fullname, input.Nickname).
+		err = s.db.QueryRow(ctx, updateFullname, input.Fullname, input.Nickname).
 			Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	} else if input.Email != "" {
-		err = s.db.QueryRow(updateEmail, input.Nickname, input.Email, input.Nickname).
+		err = s.db.QueryRow(ctx, updateEmail, input.Email, input.Nickname).
 			Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
 	}
 
 	if err == pgx.ErrNoRows {
-		return user, models.Error{Code: "404"}
+		return user, models.NewNotFound(models.EntityUser)
 	}
 
 	if pqErr, ok := err.(pgx.PgError); ok {
 		switch pqErr.Code {
 		case pgerrcode.UniqueViolation:
-			return user, models.Error{Code: "409"}
+			return user, models.NewConflict(models.EntityUser, models.ReasonAlreadyExists, "email is taken by another user")
 		default:
-			return user, models.Error{Code: "500"}
+			return user, dbConn.InternalError(err)
 		}
 	}
 
+	if err != nil {
+		return user, dbConn.InternalError(err)
+	}
+
 	return
 }
 
-func (s *storage) GetUsers(input models.ForumGetUsers, forum string) (users []models.User, err error) {
+func (s *storage) GetUsers(ctx context.Context, input models.ForumGetUsers, forum string) (users []models.User, err error) {
 
 	//func (s *storage) GetUsers(input models.ForumGetUsers, forumID int) (users []models.User, err error) {
-	var rows *pgx.Rows
+	var rows *dbConn.Rows
 	users = make([]models.User, 0)
 	if input.Since == "" && !input.Desc {
-		rows, err = s.db.Query(selectEmpty, forum, input.Limit)
+		rows, err = s.db.Query(ctx, selectEmpty, forum, input.Limit)
 	} else if input.Since == "" && input.Desc {
-		rows, err = s.db.Query(selectWithDesc, forum, input.Limit)
+		rows, err = s.db.Query(ctx, selectWithDesc, forum, input.Limit)
 	}  else if input.Since != "" && !input.Desc {
-		rows, err = s.db.Query(selectWithSince, forum, input.Since, input.Limit)
+		rows, err = s.db.Query(ctx, selectWithSince, forum, input.Since, input.Limit)
 	} else if input.Since != "" && input.Desc {
-		rows, err = s.db.Query(selectWithSinceDesc, forum, input.Since, input.Limit)
+		rows, err = s.db.Query(ctx, selectWithSinceDesc, forum, input.Since, input.Limit)
 	}
 
 	if err != nil {
-		fmt.Println(err)
-		return users, models.Error{Code: "500"}
+		return users, dbConn.InternalError(err)
 	}
 
 	defer rows.Close()
@@ -165,47 +200,169 @@ func (s *storage) GetUsers(input models.ForumGetUsers, forum string) (users []mo
 
 		err = rows.Scan(&user.Nickname, &user.Fullname, &user.About, &user.Email)
 		if err != nil {
-			fmt.Println(err)
-			return users, models.Error{Code: "500"}
+			return users, dbConn.InternalError(err)
 		}
 
 		users = append(users, user)
 	}
 
+	if err = rows.Err(); err != nil {
+		return users, dbConn.InternalError(err)
+	}
+
 	return
 }
 
-func (s *storage) GetUserForPost(input string, user *models.User) (err error) {
+func (s *storage) GetUserForPost(ctx context.Context, input string, user *models.User) (err error) {
 	user.Nickname = input
-	err = s.db.QueryRow("SELECT fullname, email, about FROM users WHERE nickname = $1", input).
+	err = s.db.QueryRow(ctx, "SELECT fullname, email, about FROM users WHERE nickname = $1", input).
 		Scan(&user.Fullname, &user.Email, &user.About)
 
 	if err != nil {
-		return models.Error{Code: "500"}
+		return dbConn.InternalError(err)
 	}
 
 	return
 }
 
-func (s *storage) GetUserIDByNickname(input string) (userID int, err error) {
-	err = s.db.QueryRow("SELECT ID FROM users WHERE nickname = $1", input).Scan(&userID)
+func (s *storage) GetUserIDByNickname(ctx context.Context, input string) (userID int, err error) {
+	err = s.db.QueryRow(ctx, "SELECT ID FROM users WHERE nickname = $1", input).Scan(&userID)
 	if err != nil {
-		return userID, models.Error{Code: "500"}
+		return userID, dbConn.InternalError(err)
 	}
 
 	return
 }
 
-func (s *storage) GetEmailConflictUser(email string) (user models.User, err error) {
-	err = s.db.QueryRow("SELECT fullname, nickname, about, email FROM users WHERE email = $1", email).
+func (s *storage) GetEmailConflictUser(ctx context.Context, email string) (user models.User, err error) {
+	err = s.db.QueryRow(ctx, "SELECT fullname, nickname, about, email FROM users WHERE email = $1", email).
 		Scan(&user.Fullname, &user.Nickname, &user.About, &user.Email)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return user, models.Error{Code: "404"}
+			return user, models.NewNotFound(models.EntityUser)
 
 		}
-		return user, models.Error{Code: "500"}
+		return user, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) RenameUser(ctx context.Context, input models.UserRename) (user models.User, err error) {
+	err = s.db.QueryRow(ctx, renameUser, input.Nickname, input.NewNickname).
+		Scan(&user.Fullname, &user.Email, &user.About, &user.Nickname)
+
+	if err == pgx.ErrNoRows {
+		return user, models.NewNotFound(models.EntityUser)
+	}
+
+	if pqErr, ok := err.(pgx.PgError); ok {
+		switch pqErr.Code {
+		case pgerrcode.UniqueViolation:
+			return user, models.NewConflict(models.EntityUser, models.ReasonAlreadyExists, "nickname is taken by another user")
+		default:
+			return user, dbConn.InternalError(err)
+		}
+	}
+
+	if err != nil {
+		return user, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// ResolveAlias returns the current nickname of the user who was called alias,
+// as long as the alias is still reserved.
+func (s *storage) ResolveAlias(ctx context.Context, alias string) (nickname string, err error) {
+	err = s.db.QueryRow(ctx, "SELECT user_nick FROM user_aliases WHERE nickname = $1 AND reserved_until > now()", alias).Scan(&nickname)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nickname, models.NewNotFound(models.EntityUser)
+		}
+		return nickname, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) AddAlias(ctx context.Context, alias string, nickname string, reservedUntil time.Time) (err error) {
+	_, err = s.db.Exec(ctx, "INSERT INTO user_aliases (nickname, user_nick, reserved_until) VALUES ($1, $2, $3) "+
+		"ON CONFLICT (nickname) DO UPDATE SET user_nick = EXCLUDED.user_nick, reserved_until = EXCLUDED.reserved_until",
+		alias, nickname, reservedUntil)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) RemoveAlias(ctx context.Context, alias string) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM user_aliases WHERE nickname = $1", alias)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) GetAliases(ctx context.Context, nickname string) (aliases []string, err error) {
+	rows, err := s.db.Query(ctx, "SELECT nickname FROM user_aliases WHERE user_nick = $1 ORDER BY nickname", nickname)
+	if err != nil {
+		return aliases, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	aliases = make([]string, 0)
+	for rows.Next() {
+		var alias string
+		if err = rows.Scan(&alias); err != nil {
+			return aliases, dbConn.InternalError(err)
+		}
+		aliases = append(aliases, alias)
+	}
+
+	if err = rows.Err(); err != nil {
+		return aliases, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// AnonymizeUser replaces the profile of the user with a pseudonym derived from
+// the user ID, which stays stable and cannot clash with a nickname anyone can
+// register, as those have no '-'. The old nicknames of the user are released.
+// It must run inside a unit of work.
+func (s *storage) AnonymizeUser(ctx context.Context, nickname string) (pseudonym string, err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM user_aliases WHERE user_nick = $1", nickname)
+	if err != nil {
+		return "", dbConn.InternalError(err)
+	}
+
+	err = s.db.QueryRow(ctx, "UPDATE users SET nickname = 'deleted-' || ID, fullname = 'Deleted user', "+
+		"email = 'deleted-' || ID || '@deleted.invalid', about = '' WHERE nickname = $1 RETURNING nickname", nickname).
+		Scan(&pseudonym)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", models.NewNotFound(models.EntityUser)
+		}
+		return "", dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// GetCounters counts what the user has written, leaving out what was deleted,
+// and the votes they have cast for threads and posts alike.
+func (s *storage) GetCounters(ctx context.Context, nickname string) (counters models.UserCounters, err error) {
+	err = s.db.QueryRow(ctx, "SELECT "+
+		"(SELECT count(*) FROM posts p JOIN threads t ON t.ID = p.thread WHERE p.author = $1 AND NOT p.deleted AND t.state <> 'deleted'), "+
+		"(SELECT count(*) FROM threads WHERE author = $1 AND state <> 'deleted'), "+
+		"(SELECT count(*) FROM votes WHERE user_nick = $1) + (SELECT count(*) FROM post_votes WHERE user_nick = $1)", nickname).
+		Scan(&counters.Posts, &counters.Threads, &counters.Votes)
+	if err != nil {
+		return counters, dbConn.InternalError(err)
 	}
 
 	return
@@ -1,104 +1,114 @@
 package voteStorage
 
 import (
+	"context"
 	"database/sql"
 	"github.com/jackc/pgerrcode"
+	"github.com/EgorAist/TP_DB_project/internal/storages/dbConn"
 	"github.com/jackc/pgx"
 	"github.com/EgorAist/TP_DB_project/internal/models"
 )
 
 type Storage interface {
-	CreateVote(vote models.Vote, update bool) (thread models.Thread, err error)
-	CheckDoubleVote(vote models.Vote) (thread models.Thread, err error)
+	CreateVote(ctx context.Context, vote models.Vote, update bool) (thread models.Thread, err error)
+	GetVoice(ctx context.Context, vote models.Vote) (voice int, found bool, err error)
+	DeleteUserVotes(ctx context.Context, nickname string) (votes int, err error)
+	GetUserVotes(ctx context.Context, nickname string) (votes []models.UserVote, err error)
+
+	GetPostVoice(ctx context.Context, vote models.PostVote) (voice int, found bool, err error)
+	CreatePostVote(ctx context.Context, vote models.PostVote, score int) (err error)
+	AddReaction(ctx context.Context, reaction models.PostReaction) (err error)
+	RemoveReaction(ctx context.Context, reaction models.PostReaction) (err error)
+	GetReactions(ctx context.Context, post int) (reactions map[string]int, err error)
+	DeleteUserPostVotes(ctx context.Context, nickname string) (votes int, err error)
+	GetUserPostVotes(ctx context.Context, nickname string) (votes []models.UserPostVote, err error)
+	GetUserReactions(ctx context.Context, nickname string) (reactions []models.UserPostReaction, err error)
 }
 
 type storage struct {
-	db *pgx.ConnPool
+	db *dbConn.DB
 
 }
 
-func NewStorage(db *pgx.ConnPool) Storage {
+func NewStorage(db *dbConn.DB) Storage {
 	return &storage{
 		db: db,
 	}
 }
 
+func init() {
+	dbConn.RegisterQueries(map[string]string{
+		"insertVote":            insertVote,
+		"createThreadVotesUp":   createThreadVotesUp,
+		"createThreadVotesDown": createThreadVotesDown,
+		"updateThreadVotesUp":   updateThreadVotesUp,
+		"updateThreadVotesDown": updateThreadVotesDown,
+		"deleteUserVotes":       deleteUserVotes,
+		"insertPostVote":        insertPostVote,
+		"deleteUserPostVotes":   deleteUserPostVotes,
+	})
+}
+
 var (
 	insertVote            = "INSERT INTO votes (user_nick, voice, thread) VALUES ($1, $2, $3) ON CONFLICT ON CONSTRAINT uniq_votes DO UPDATE SET voice = EXCLUDED.voice;"
-	createThreadVotesUp   = "UPDATE threads SET votes = votes + 1 WHERE ID = $1 RETURNING ID, author, created, forum, message, slug, title, votes"
-	createThreadVotesDown = "UPDATE threads SET votes = votes - 1 WHERE ID = $1 RETURNING ID, author, created, forum, message, slug, title, votes"
+	createThreadVotesUp   = "UPDATE threads SET votes = votes + 1 WHERE ID = $1 RETURNING ID, author, created, forum, message, slug, title, votes, state"
+	createThreadVotesDown = "UPDATE threads SET votes = votes - 1 WHERE ID = $1 RETURNING ID, author, created, forum, message, slug, title, votes, state"
 
-	updateThreadVotesUp   = "UPDATE threads SET votes = votes + 2 WHERE ID = $1 RETURNING ID, author, created, forum, message, slug, title, votes"
-	updateThreadVotesDown = "UPDATE threads SET votes = votes - 2 WHERE ID = $1 RETURNING ID, author, created, forum, message, slug, title, votes"
+	updateThreadVotesUp   = "UPDATE threads SET votes = votes + 2 WHERE ID = $1 RETURNING ID, author, created, forum, message, slug, title, votes, state"
+	updateThreadVotesDown = "UPDATE threads SET votes = votes - 2 WHERE ID = $1 RETURNING ID, author, created, forum, message, slug, title, votes, state"
 )
 
-func (s *storage) CreateVote(vote models.Vote, update bool) (thread models.Thread, err error) {
+// CreateVote must run inside a unit of work: it inserts the vote and adjusts
+// threads.votes, which have to commit together.
+func (s *storage) CreateVote(ctx context.Context, vote models.Vote, update bool) (thread models.Thread, err error) {
 	boolVoice := getBoolVoice(vote)
-	tx, err := s.db.Begin()
-	if err != nil {
-		return thread, models.Error{Code: "500"}
-	}
 
-	_, err = tx.Exec("SET LOCAL synchronous_commit TO OFF")
+	_, err = s.db.Exec(ctx, "SET LOCAL synchronous_commit TO OFF")
 	if err != nil {
-		if txErr := tx.Rollback(); txErr != nil {
-			return thread, models.Error{Code: "500"}
-		}
-		return thread, models.Error{Code: "500"}
+		return thread, dbConn.InternalError(err)
 	}
 
-	_, err = tx.Exec(insertVote, vote.User, boolVoice, vote.Thread.ThreadID)
+	_, err = s.db.Exec(ctx, insertVote, vote.User, boolVoice, vote.Thread.ThreadID)
 	if err != nil {
-		if txErr := tx.Rollback(); txErr != nil {
-			return thread, models.Error{Code: "500"}
-		}
 		if pqErr, ok := err.(pgx.PgError); ok {
 			switch pqErr.Code {
 			case pgerrcode.ForeignKeyViolation:
-				return thread, models.Error{Code: "404"}
+				return thread, models.NewNotFound(dbConn.ReferencedEntity(pqErr))
 			default:
-				return thread, models.Error{Code: "500"}
+				return thread, dbConn.InternalError(err)
 			}
 		}
-		return thread, models.Error{Code: "500"}
+		return thread, dbConn.InternalError(err)
 	}
 
 	slug := sql.NullString{}
 
 	if update {
 		if boolVoice {
-			err = tx.QueryRow(updateThreadVotesUp, vote.Thread.ThreadID).
-				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes)
+			err = s.db.QueryRow(ctx, updateThreadVotesUp, vote.Thread.ThreadID).
+				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.State)
 		} else {
-			err = tx.QueryRow(updateThreadVotesDown, vote.Thread.ThreadID).
-				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes)
+			err = s.db.QueryRow(ctx, updateThreadVotesDown, vote.Thread.ThreadID).
+				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.State)
 		}
 	} else {
 		if boolVoice {
-			err = tx.QueryRow(createThreadVotesUp, vote.Thread.ThreadID).
-				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes)
+			err = s.db.QueryRow(ctx, createThreadVotesUp, vote.Thread.ThreadID).
+				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.State)
 		} else {
-			err = tx.QueryRow(createThreadVotesDown, vote.Thread.ThreadID).
-				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes)
+			err = s.db.QueryRow(ctx, createThreadVotesDown, vote.Thread.ThreadID).
+				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes, &thread.State)
 		}
 	}
 
 	if err != nil {
-		if txErr := tx.Rollback(); txErr != nil {
-			return thread, models.Error{Code: "500"}
-		}
-
-		return thread, models.Error{Code: "500"}
+		return thread, dbConn.InternalError(err)
 	}
 
 	if slug.Valid {
 		thread.Slug = slug.String
 	}
 
-	if commitErr := tx.Commit(); commitErr != nil {
-		return thread, models.Error{Code: "500"}
-	}
-
 	return
 }
 
@@ -109,34 +119,237 @@ func getBoolVoice(vote models.Vote) bool {
 	return false
 }
 
-func (s *storage) CheckDoubleVote(vote models.Vote) (thread models.Thread, err error) {
-	boolVoice := getBoolVoice(vote)
+// GetVoice returns the voice the user has already given to the thread, if any.
+func (s *storage) GetVoice(ctx context.Context, vote models.Vote) (voice int, found bool, err error) {
 	var oldVoice bool
-	err = s.db.QueryRow("SELECT voice FROM votes WHERE user_nick = $1 AND thread = $2", vote.User, vote.Thread.ThreadID).
+	err = s.db.QueryRow(ctx, "SELECT voice FROM votes WHERE user_nick = $1 AND thread = $2", vote.User, vote.Thread.ThreadID).
 				Scan(&oldVoice)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return thread, nil
+			return 0, false, nil
 		}
-		return thread, models.Error{Code: "500"}
+		return 0, false, dbConn.InternalError(err)
+	}
+
+	if oldVoice {
+		return 1, true, nil
 	}
+	return -1, true, nil
+}
 
-	if oldVoice != boolVoice {
-		return thread, models.Error{Code: "101"}
+// deleteUserVotes takes the voices back from threads.votes as it removes them.
+const deleteUserVotes = `
+	WITH removed AS (
+		DELETE FROM votes WHERE user_nick = $1 RETURNING thread, voice
+	), adjusted AS (
+		UPDATE threads t SET votes = t.votes - r.total
+		FROM (SELECT thread, sum(CASE WHEN voice THEN 1 ELSE -1 END) AS total FROM removed GROUP BY thread) r
+		WHERE t.ID = r.thread
+	)
+	SELECT count(*)::INTEGER FROM removed
+`
+
+func (s *storage) DeleteUserVotes(ctx context.Context, nickname string) (votes int, err error) {
+	err = s.db.QueryRow(ctx, deleteUserVotes, nickname).Scan(&votes)
+	if err != nil {
+		return 0, dbConn.InternalError(err)
 	}
 
-	slug := sql.NullString{}
-	err = s.db.QueryRow("SELECT ID, author, created, forum, message, slug, title, votes FROM threads WHERE ID = $1", vote.Thread.ThreadID).
-				Scan(&thread.ID, &thread.Author, &thread.Created, &thread.Forum, &thread.Message, &slug, &thread.Title, &thread.Votes)
+	return
+}
 
-	if slug.Valid {
-		thread.Slug = slug.String
+func (s *storage) GetUserVotes(ctx context.Context, nickname string) (votes []models.UserVote, err error) {
+	rows, err := s.db.Query(ctx, "SELECT thread, voice FROM votes WHERE user_nick = $1 ORDER BY thread", nickname)
+	if err != nil {
+		return votes, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	votes = make([]models.UserVote, 0)
+	for rows.Next() {
+		vote := models.UserVote{Voice: -1}
+		var voice bool
+		if err = rows.Scan(&vote.Thread, &voice); err != nil {
+			return votes, dbConn.InternalError(err)
+		}
+		if voice {
+			vote.Voice = 1
+		}
+		votes = append(votes, vote)
+	}
+
+	if err = rows.Err(); err != nil {
+		return votes, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// GetPostVoice returns the voice the user has already given to the post, if
+// any.
+func (s *storage) GetPostVoice(ctx context.Context, vote models.PostVote) (voice int, found bool, err error) {
+	var oldVoice bool
+	err = s.db.QueryRow(ctx, "SELECT voice FROM post_votes WHERE post = $1 AND user_nick = $2", vote.Post, vote.User).
+		Scan(&oldVoice)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, false, nil
+		}
+		return 0, false, dbConn.InternalError(err)
+	}
+
+	if oldVoice {
+		return 1, true, nil
+	}
+	return -1, true, nil
+}
+
+const insertPostVote = "INSERT INTO post_votes (post, user_nick, voice) VALUES ($1, $2, $3) ON CONFLICT (post, user_nick) DO UPDATE SET voice = EXCLUDED.voice"
+
+// CreatePostVote must run inside a unit of work: it stores the vote and adds
+// score to the score of the post.
+func (s *storage) CreatePostVote(ctx context.Context, vote models.PostVote, score int) (err error) {
+	_, err = s.db.Exec(ctx, insertPostVote, vote.Post, vote.User, vote.Voice == 1)
+	if err != nil {
+		if pqErr, ok := err.(pgx.PgError); ok && pqErr.Code == pgerrcode.ForeignKeyViolation {
+			if pqErr.ConstraintName == "post_votes_post_fkey" {
+				return models.NewNotFound(models.EntityPost)
+			}
+			return models.NewNotFound(models.EntityUser)
+		}
+		return dbConn.InternalError(err)
+	}
+
+	_, err = s.db.Exec(ctx, "UPDATE posts SET score = score + $2 WHERE ID = $1", vote.Post, score)
+	if err != nil {
+		return dbConn.InternalError(err)
+	}
+
+	return
+}
+
+// AddReaction gives the reaction to the post; giving it again changes nothing.
+func (s *storage) AddReaction(ctx context.Context, reaction models.PostReaction) (err error) {
+	_, err = s.db.Exec(ctx, "INSERT INTO post_reactions (post, user_nick, reaction) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING",
+		reaction.Post, reaction.User, reaction.Reaction)
+	if err != nil {
+		if pqErr, ok := err.(pgx.PgError); ok && pqErr.Code == pgerrcode.ForeignKeyViolation {
+			if pqErr.ConstraintName == "post_reactions_post_fkey" {
+				return models.NewNotFound(models.EntityPost)
+			}
+			return models.NewNotFound(models.EntityUser)
+		}
+		return dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) RemoveReaction(ctx context.Context, reaction models.PostReaction) (err error) {
+	_, err = s.db.Exec(ctx, "DELETE FROM post_reactions WHERE post = $1 AND user_nick = $2 AND reaction = $3",
+		reaction.Post, reaction.User, reaction.Reaction)
+	if err != nil {
+		return dbConn.InternalError(err)
 	}
 
+	return
+}
+
+// GetReactions counts the reactions of each kind given to the post.
+func (s *storage) GetReactions(ctx context.Context, post int) (reactions map[string]int, err error) {
+	rows, err := s.db.Query(ctx, "SELECT reaction, count(*) FROM post_reactions WHERE post = $1 GROUP BY reaction", post)
 	if err != nil {
-		return thread, models.Error{Code: "500"}
+		return reactions, dbConn.InternalError(err)
 	}
+	defer rows.Close()
 
-	return thread, models.Error{Code: "409"}
+	reactions = make(map[string]int)
+	for rows.Next() {
+		var reaction string
+		var count int
+		if err = rows.Scan(&reaction, &count); err != nil {
+			return reactions, dbConn.InternalError(err)
+		}
+		reactions[reaction] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return reactions, dbConn.InternalError(err)
+	}
+
+	return
 }
 
+// deleteUserPostVotes takes the voices back from posts.score as it removes
+// them, and removes the reactions of the user along with them.
+const deleteUserPostVotes = `
+	WITH removed AS (
+		DELETE FROM post_votes WHERE user_nick = $1 RETURNING post, voice
+	), adjusted AS (
+		UPDATE posts p SET score = p.score - r.total
+		FROM (SELECT post, sum(CASE WHEN voice THEN 1 ELSE -1 END) AS total FROM removed GROUP BY post) r
+		WHERE p.ID = r.post
+	), reactions AS (
+		DELETE FROM post_reactions WHERE user_nick = $1
+	)
+	SELECT count(*)::INTEGER FROM removed
+`
+
+func (s *storage) DeleteUserPostVotes(ctx context.Context, nickname string) (votes int, err error) {
+	err = s.db.QueryRow(ctx, deleteUserPostVotes, nickname).Scan(&votes)
+	if err != nil {
+		return 0, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) GetUserPostVotes(ctx context.Context, nickname string) (votes []models.UserPostVote, err error) {
+	rows, err := s.db.Query(ctx, "SELECT post, voice FROM post_votes WHERE user_nick = $1 ORDER BY post", nickname)
+	if err != nil {
+		return votes, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	votes = make([]models.UserPostVote, 0)
+	for rows.Next() {
+		vote := models.UserPostVote{Voice: -1}
+		var voice bool
+		if err = rows.Scan(&vote.Post, &voice); err != nil {
+			return votes, dbConn.InternalError(err)
+		}
+		if voice {
+			vote.Voice = 1
+		}
+		votes = append(votes, vote)
+	}
+
+	if err = rows.Err(); err != nil {
+		return votes, dbConn.InternalError(err)
+	}
+
+	return
+}
+
+func (s *storage) GetUserReactions(ctx context.Context, nickname string) (reactions []models.UserPostReaction, err error) {
+	rows, err := s.db.Query(ctx, "SELECT post, reaction FROM post_reactions WHERE user_nick = $1 ORDER BY post, reaction", nickname)
+	if err != nil {
+		return reactions, dbConn.InternalError(err)
+	}
+	defer rows.Close()
+
+	reactions = make([]models.UserPostReaction, 0)
+	for rows.Next() {
+		reaction := models.UserPostReaction{}
+		if err = rows.Scan(&reaction.Post, &reaction.Reaction); err != nil {
+			return reactions, dbConn.InternalError(err)
+		}
+		reactions = append(reactions, reaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return reactions, dbConn.InternalError(err)
+	}
+
+	return
+}